@@ -0,0 +1,225 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Arg implements a declared positional argument of a command.
+//
+// Only the last declared argument of a command can be variadic, and optional
+// arguments must be declared after the required ones.
+type Arg struct {
+	// Name is the name of the argument shown in the usage output.
+	Name string
+
+	// Description is the message shown for the argument in the usage output.
+	Description string
+
+	// Required makes the argument mandatory.
+	Required bool
+
+	// Variadic makes the argument accept any number of values.
+	Variadic bool
+
+	// Complete returns the completion candidates for the argument.
+	Complete CompletionFunc
+}
+
+// usage returns the representation of the argument in the usage line.
+func (a *Arg) usage() string {
+	name := a.Name
+	if a.Variadic {
+		name += "..."
+	}
+
+	if a.Required {
+		return "<" + name + ">"
+	}
+
+	return "[" + name + "]"
+}
+
+// ArgsValidator validates the positional arguments of a command.
+type ArgsValidator func(c *Command, args []string) error
+
+// NoArgs returns an error if there is any positional argument.
+func NoArgs(c *Command, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("accepts no arguments, received %d", len(args))
+	}
+
+	return nil
+}
+
+// ExactArgs returns an ArgsValidator that fails if there are not exactly n
+// positional arguments.
+func ExactArgs(n int) ArgsValidator {
+	return func(c *Command, args []string) error {
+		if len(args) != n {
+			return fmt.Errorf("accepts %d argument(s), received %d", n, len(args))
+		}
+
+		return nil
+	}
+}
+
+// MinimumArgs returns an ArgsValidator that fails if there are less than n
+// positional arguments.
+func MinimumArgs(n int) ArgsValidator {
+	return func(c *Command, args []string) error {
+		if len(args) < n {
+			return fmt.Errorf("requires at least %d argument(s), received %d", n, len(args))
+		}
+
+		return nil
+	}
+}
+
+// MaximumArgs returns an ArgsValidator that fails if there are more than n
+// positional arguments.
+func MaximumArgs(n int) ArgsValidator {
+	return func(c *Command, args []string) error {
+		if len(args) > n {
+			return fmt.Errorf("accepts at most %d argument(s), received %d", n, len(args))
+		}
+
+		return nil
+	}
+}
+
+// RangeArgs returns an ArgsValidator that fails if the number of positional
+// arguments is not between min and max, both included.
+func RangeArgs(min int, max int) ArgsValidator {
+	return func(c *Command, args []string) error {
+		if len(args) < min || len(args) > max {
+			return fmt.Errorf("accepts between %d and %d argument(s), received %d", min, max, len(args))
+		}
+
+		return nil
+	}
+}
+
+// AddArg declares a positional argument for this Command.
+//
+// The declared arguments are validated when the command is executed, and are
+// shown in its usage output.
+func (c *Command) AddArg(arg *Arg) {
+	c.positionals = append(c.positionals, arg)
+}
+
+// Args returns the list of declared positional arguments of this command.
+func (c *Command) Args() []*Arg {
+	return c.positionals
+}
+
+// positional returns the declared argument at the position i of the
+// positional arguments, or nil if there is none.
+func (c *Command) positional(i int) *Arg {
+	if i < len(c.positionals) {
+		return c.positionals[i]
+	}
+
+	if len(c.positionals) > 0 && c.positionals[len(c.positionals)-1].Variadic {
+		return c.positionals[len(c.positionals)-1]
+	}
+
+	return nil
+}
+
+// acceptsArgs checks if this command accepts positional arguments, that is if
+// it declares them or it validates them.
+func (c *Command) acceptsArgs() bool {
+	return len(c.positionals) > 0 || c.ValidateArgs != nil
+}
+
+// validateArgs validates the positional arguments of this command against its
+// declared arguments and its ValidateArgs.
+func (c *Command) validateArgs() error {
+	args := c.Arguments()
+
+	if len(c.positionals) > 0 {
+		min := 0
+		max := len(c.positionals)
+		for _, arg := range c.positionals {
+			if arg.Required {
+				min++
+			}
+			if arg.Variadic {
+				max = -1
+			}
+		}
+
+		if len(args) < min {
+			missing := make([]string, 0)
+			for _, arg := range c.positionals[len(args):] {
+				if arg.Required {
+					missing = append(missing, arg.Name)
+				}
+			}
+			return &ArgumentsError{
+				Command: c,
+				Err:     fmt.Errorf("missing required argument(s): %s", strings.Join(missing, ", ")),
+			}
+		}
+
+		if max >= 0 && len(args) > max {
+			return &ArgumentsError{
+				Command: c,
+				Err:     fmt.Errorf("accepts at most %d argument(s), received %d", max, len(args)),
+			}
+		}
+	}
+
+	if c.ValidateArgs != nil {
+		err := c.ValidateArgs(c, args)
+		if err != nil {
+			return &ArgumentsError{Command: c, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// argsUsage returns the representation of the positional arguments of this
+// command in the usage line.
+//
+// The sub-command is shown as optional when the command is runnable by
+// itself.
+func (c *Command) argsUsage() string {
+	usage := ""
+	switch {
+	case len(c.commands) > 0 && (c.Run != nil || c.RunContext != nil):
+		usage += " [command]"
+	case len(c.commands) > 0:
+		usage += " <command>"
+	}
+
+	switch {
+	case len(c.positionals) > 0:
+		for _, arg := range c.positionals {
+			usage += " " + arg.usage()
+		}
+	case len(c.commands) == 0 || c.ValidateArgs != nil:
+		usage += " [args]"
+	}
+
+	return usage
+}
@@ -0,0 +1,177 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_Arguments_positionals(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-o", LongName: "-output", Value: ""})
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.AddFlag(&cli.Flag{ShortName: "-n", LongName: "-name", Value: ""})
+	subCommand1.AddFlag(&cli.Flag{ShortName: "-f", LongName: "-force", Value: "false"})
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1", "-name", "foo", "argument1", "-f", "argument2"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := []string{"argument1", "argument2"}
+	if !reflect.DeepEqual(subCommand1.Arguments(), expected) {
+		t.Fatalf("Expected %q but got %q", expected, subCommand1.Arguments())
+	}
+}
+
+func TestCommand_Arguments_withSubCommands(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddArg(&cli.Arg{Name: "file", Variadic: true})
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.Run = func(c *cli.Command) error {
+		t.Fatalf("Expected Run not to be called")
+
+		return nil
+	}
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"file1", "subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := []string{"file1", "subCommand1"}
+	if !reflect.DeepEqual(rootCommand.Arguments(), expected) {
+		t.Fatalf("Expected %q but got %q", expected, rootCommand.Arguments())
+	}
+}
+
+func TestCommand_AddArg_validation(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{}, false},
+		{[]string{"src"}, false},
+		{[]string{"src", "dst"}, true},
+		{[]string{"src", "dst", "mode"}, true},
+		{[]string{"src", "dst", "mode", "extra"}, false},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.AddArg(&cli.Arg{Name: "source", Required: true})
+		rootCommand.AddArg(&cli.Arg{Name: "destination", Required: true})
+		rootCommand.AddArg(&cli.Arg{Name: "mode"})
+
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if tc.valid && err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+		if !tc.valid {
+			var argsErr *cli.ArgumentsError
+			if !errors.As(err, &argsErr) {
+				t.Fatalf("Expected *cli.ArgumentsError but got %#v for %q", err, tc.args)
+			}
+		}
+	}
+}
+
+func TestCommand_ValidateArgs(t *testing.T) {
+	testCases := []struct {
+		validator cli.ArgsValidator
+		args      []string
+		valid     bool
+	}{
+		{cli.NoArgs, []string{}, true},
+		{cli.NoArgs, []string{"a"}, false},
+		{cli.ExactArgs(2), []string{"a", "b"}, true},
+		{cli.ExactArgs(2), []string{"a"}, false},
+		{cli.MinimumArgs(1), []string{"a", "b"}, true},
+		{cli.MinimumArgs(1), []string{}, false},
+		{cli.MaximumArgs(1), []string{"a"}, true},
+		{cli.MaximumArgs(1), []string{"a", "b"}, false},
+		{cli.RangeArgs(1, 2), []string{"a", "b"}, true},
+		{cli.RangeArgs(1, 2), []string{"a", "b", "c"}, false},
+		{cli.RangeArgs(1, 2), []string{}, false},
+	}
+
+	for i, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.ValidateArgs = tc.validator
+
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if tc.valid && err != nil {
+			t.Fatalf("Expected no error but got %s for case %d", err, i)
+		}
+		if !tc.valid && err == nil {
+			t.Fatalf("Expected error but got nil for case %d", i)
+		}
+	}
+}
+
+func TestCommand_Usage_arguments(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddArg(&cli.Arg{Name: "source", Description: "Source file", Required: true})
+	rootCommand.AddArg(&cli.Arg{Name: "destination", Description: "Destination files", Variadic: true})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-help"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := "usage: programName [-help] <source> [destination...]\n"
+	expected += "\n"
+	expected += "Arguments:\n"
+	expected += "  source       Source file\n"
+	expected += "  destination  Destination files\n"
+	expected += "\n"
+	expected += "Flags:\n"
+	expected += "  -h, -help    Show help message\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected %q but got %q", expected, buf.String())
+	}
+}
+
+func TestArgumentsError_commandPath(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.ValidateArgs = cli.NoArgs
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1", "a"})
+	if err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+
+	expected := "invalid arguments for \"programName subCommand1\": accepts no arguments, received 1"
+	if err.Error() != expected {
+		t.Fatalf("Expected %q but got %q", expected, err.Error())
+	}
+}
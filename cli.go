@@ -17,12 +17,47 @@
 
 package cli
 
+import (
+	"context"
+	"os"
+)
+
 // Execute executes the root command.
 //
 // Execute uses the command arguments and run through the command tree finding
 // appropriate matches for commands and then corresponding flags.
+//
+// The command arguments are the ones set with Command.SetArgs, or os.Args[1:]
+// if none were set.
 func Execute(cmd *Command) error {
-	err := cmd.execute()
+	err := ExecuteContext(context.Background(), cmd)
+
+	return err
+}
+
+// ExecuteArgs executes the root command using args as the command arguments.
+//
+// It behaves like Execute but does not read os.Args, so commands can be driven
+// from tests or embedded callers.
+func ExecuteArgs(cmd *Command, args []string) error {
+	err := cmd.execute(context.Background(), args)
+
+	return err
+}
+
+// ExecuteContext executes the root command like Execute, making ctx available
+// to the command selected for execution through Command.Context and
+// Command.RunContext.
+//
+// If the root command HandleSignals is set, the context is cancelled when the
+// process receives a SIGINT or a SIGTERM signal, see SignalContext.
+func ExecuteContext(ctx context.Context, cmd *Command) error {
+	args := cmd.commandLine
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	err := cmd.execute(ctx, args)
 
 	return err
 }
@@ -18,23 +18,125 @@
 package cli_test
 
 import (
-	"os"
+	"bytes"
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/goombaio/cli"
 )
 
 func TestCLI_Execute(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := &cli.Command{
 		Name:             "programName",
 		ShortDescription: "rootCommand Description",
 		LongDescription:  "rootCommand Long Description",
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+}
+
+func TestCLI_Execute_unknownCommand(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Run = func(c *cli.Command) error {
+		t.Fatalf("Expected Run not to be called")
+
+		return nil
+	}
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	subCommand1 := cli.NewCommand("deploy", "deploy Description")
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"delpoy"})
+	var cmdErr *cli.UnknownCommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected *cli.UnknownCommandError but got %#v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no output but got %q", buf.String())
+	}
+}
+
+func TestCLI_Execute_showUsageOnError(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.ShowUsageOnError = true
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	subCommand1 := cli.NewCommand("deploy", "deploy Description")
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"deploy", "-undefined"})
+	var flagErr *cli.UnknownFlagError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.UnknownFlagError but got %#v", err)
+	}
+	if flagErr.Command != subCommand1 {
+		t.Fatalf("Expected %s but got %s", subCommand1.Name, flagErr.Command.Name)
+	}
+
+	expected := new(bytes.Buffer)
+	subCommand1.SetOutput(expected)
+	subCommand1.Usage()
+	if buf.String() != expected.String() {
+		t.Fatalf("Expected %q but got %q", expected.String(), buf.String())
+	}
+}
+
+func TestCLI_ExecuteContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.RunContext = func(ctx context.Context, c *cli.Command) error {
+		if ctx.Value(key{}) != "value" {
+			t.Fatalf("Expected context value %q but got %v", "value", ctx.Value(key{}))
+		}
+		if c.Context() != ctx {
+			t.Fatalf("Expected Context() to be the execution context")
+		}
+		if c.Parent().Context() != ctx {
+			t.Fatalf("Expected parent Context() to be the execution context")
+		}
+
+		return nil
+	}
+	subCommand1.Run = func(c *cli.Command) error {
+		t.Fatalf("Expected Run not to be called")
+
+		return nil
+	}
+	rootCommand.AddCommand(subCommand1)
+
+	rootCommand.SetArgs([]string{"subCommand1"})
+
+	err := cli.ExecuteContext(ctx, rootCommand)
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
+
+func TestCLI_ExecuteContext_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Run = func(c *cli.Command) error {
+		return c.Context().Err()
+	}
+
+	rootCommand.SetArgs([]string{})
+
+	err := cli.ExecuteContext(ctx, rootCommand)
+	if err != context.Canceled {
+		t.Fatalf("Expected %s but got %v", context.Canceled, err)
+	}
+}
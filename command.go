@@ -18,12 +18,23 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"io"
 	"os"
+	"strings"
+	"text/template"
 
 	"github.com/goombaio/log"
 )
 
+// RunFunc is the function type of the command actions and hooks.
+type RunFunc func(c *Command) error
+
+// Middleware wraps a RunFunc returning a new RunFunc, which usually does some
+// work before and after calling next.
+type Middleware func(next RunFunc) RunFunc
+
 // Command type implements a command or subcommand.
 //
 // A command is just that, a command for your application.
@@ -41,8 +52,94 @@ type Command struct {
 	// '<this-command> -help' output.
 	LongDescription string
 
+	// Example is shown in the usage output, in its own section, as it is.
+	Example string
+
+	// Aliases are the alternative names the command can be invoked with.
+	// E.g. 'rm' for the command 'remove'.
+	Aliases []string
+
+	// PrefixMatching makes any unambiguous prefix of the name or of an alias
+	// of a sub-command of this command, or of the sub-commands of them, to
+	// select the sub-command. E.g. 'rem' for the command 'remove'.
+	PrefixMatching bool
+
 	// Run is the actual work that the command will do when it is invoked.
-	Run func(c *Command) error
+	Run RunFunc
+
+	// RunContext is like Run but it also receives the context the command
+	// was executed with. If set, it is used instead of Run.
+	RunContext func(ctx context.Context, c *Command) error
+
+	// PersistentPreRun runs before PreRun, on this command and on every
+	// sub-command of it selected for execution.
+	PersistentPreRun RunFunc
+
+	// PreRun runs before Run.
+	PreRun RunFunc
+
+	// PostRun runs after Run.
+	PostRun RunFunc
+
+	// PersistentPostRun runs after PostRun, on this command and on every
+	// sub-command of it selected for execution.
+	PersistentPostRun RunFunc
+
+	// EnvPrefix enables the automatic binding of the flags of this command,
+	// and of its sub-commands, to environment variables named after the
+	// prefix, the path of sub-commands and the flag name. E.g. with the
+	// prefix "PROGRAM" the flag -port of the sub-command "serve" is bound to
+	// PROGRAM_SERVE_PORT.
+	EnvPrefix string
+
+	// ValidateArgs validates the positional arguments of the command, in
+	// addition to the validation of its declared arguments. See AddArg.
+	ValidateArgs ArgsValidator
+
+	// HandleSignals makes the execution context to be cancelled when the
+	// process receives a SIGINT or a SIGTERM signal. It is only used on the
+	// root command.
+	HandleSignals bool
+
+	// GNUFlags enables the GNU flag syntax on this command and on its
+	// sub-commands. Short names are single letters given with one dash, which
+	// can be combined as in -xvf and can have their value attached as in
+	// -ofile, long names are given with two dashes as in --verbose, and the
+	// argument "--" terminates the flags, so the arguments that follow it are
+	// positional arguments.
+	GNUFlags bool
+
+	// ParseMode is how the flags of this command are parsed among its
+	// positional arguments. By default they are interspersed.
+	ParseMode ParseMode
+
+	// Group is the title of the group the command is shown under in the
+	// usage output of its parent, see SetCommandGroups. Ungrouped commands
+	// are shown under a default heading.
+	Group string
+
+	// Hidden hides the command from the usage output, the suggestions and
+	// the completions. It is still executed when invoked by its name.
+	Hidden bool
+
+	// Deprecated marks the command as deprecated with a message that usually
+	// names its replacement, e.g. "use 'programName new' instead". A
+	// deprecated command still works, but a warning with the message is
+	// emitted when it is executed, and it is hidden like a Hidden command.
+	Deprecated string
+
+	// LogDeprecations makes the warnings about deprecated commands and flags
+	// to be logged with the Logger of the command executed, instead of being
+	// written to its output. It applies to this command and to its
+	// sub-commands.
+	LogDeprecations bool
+
+	// ShowUsageOnError makes Execute print the usage of the command related
+	// to a parse error, before returning the error.
+	ShowUsageOnError bool
+
+	// parent is the command this command was added to as a subcommand.
+	parent *Command
 
 	// commands are the list of subcommands that a command have associated with
 	// it.
@@ -52,6 +149,14 @@ type Command struct {
 	// it.
 	arguments []string
 
+	// commandLine are the command line arguments set with SetArgs, used
+	// instead of os.Args when executing the command.
+	commandLine []string
+
+	// positionals are the list of positional arguments declared for the
+	// command.
+	positionals []*Arg
+
 	// flags are the list of flags that a command have associated with it.
 	flags []*Flag
 
@@ -60,6 +165,38 @@ type Command struct {
 
 	// logger is the log.Logger being used
 	logger log.Logger
+
+	// ctx is the context the command is being executed with.
+	ctx context.Context
+
+	// flagGroups are the groups of flags subject to a constraint.
+	flagGroups []*flagGroup
+
+	// configFlag is the flag whose value is the path of the configuration
+	// file, see AddConfigFlag.
+	configFlag *Flag
+
+	// middlewares are the list of middlewares that wrap the command action.
+	middlewares []Middleware
+
+	// commandGroups is the order of the groups of the sub-commands, see
+	// SetCommandGroups.
+	commandGroups []string
+
+	// helpTopic makes the command a help topic, see AddHelpTopic.
+	helpTopic bool
+
+	// usageTemplate is the template of the usage output, see
+	// SetUsageTemplate.
+	usageTemplate string
+
+	// templateFuncs are the functions added to the usage template.
+	templateFuncs template.FuncMap
+
+	// rawArgs makes the command, when it is a subcommand of the executed
+	// command, to take all the arguments that follow it verbatim as its
+	// positional arguments, without parsing them.
+	rawArgs bool
 }
 
 // NewCommand creates a new Command.
@@ -81,9 +218,18 @@ func NewCommand(name string, shortDescription string) *Command {
 		logger: log.NewNoopLogger(),
 	}
 
+	// Setup command default flag set
+	cmd.setupDefaultFlags()
+
 	return cmd
 }
 
+// Parent returns the command this command was added to as a sub-command, or
+// nil for the root command.
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
 // Commands returns the list of sub-commands of this command.
 func (c *Command) Commands() []*Command {
 	return c.commands
@@ -95,7 +241,94 @@ func (c *Command) Command(id int) *Command {
 	return c.commands[id]
 }
 
-// Arguments returns the list of arguments of this command.
+// commandName returns the sub-command of this command given its name or one
+// of its aliases.
+func (c *Command) commandName(name string) *Command {
+	for _, command := range c.Commands() {
+		if command.hasName(name) {
+			return command
+		}
+	}
+
+	return nil
+}
+
+// findCommand returns the sub-command of this command given its name, one of
+// its aliases or, if prefix matching is enabled, a prefix of them. It returns
+// nil if there is none.
+//
+// An *AmbiguousCommandError is returned if the prefix matches more than one
+// sub-command.
+func (c *Command) findCommand(name string) (*Command, error) {
+	command := c.commandName(name)
+	if command != nil || !c.prefixMatching() {
+		return command, nil
+	}
+
+	matches := make([]*Command, 0)
+	for _, command := range c.Commands() {
+		if !command.visible() {
+			continue
+		}
+		for _, alias := range append([]string{command.Name}, command.Aliases...) {
+			if strings.HasPrefix(alias, name) {
+				matches = append(matches, command)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+
+	candidates := make([]string, 0, len(matches))
+	for _, match := range matches {
+		candidates = append(candidates, match.Name)
+	}
+
+	return nil, &AmbiguousCommandError{Command: c, Name: name, Candidates: candidates}
+}
+
+// visible checks if the command is shown in the usage output, the
+// suggestions and the completions.
+func (c *Command) visible() bool {
+	return !c.Hidden && c.Deprecated == ""
+}
+
+// hasName checks if name is the name or one of the aliases of this command.
+func (c *Command) hasName(name string) bool {
+	if c.Name == name {
+		return true
+	}
+
+	for _, alias := range c.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prefixMatching checks if prefix matching is enabled on this command or on
+// any of its parents.
+func (c *Command) prefixMatching() bool {
+	for command := c; command != nil; command = command.parent {
+		if command.PrefixMatching {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Arguments returns the list of positional arguments of this command.
+//
+// Flags, flag values and sub-command names are not included.
 func (c *Command) Arguments() []string {
 	return c.arguments
 }
@@ -107,10 +340,80 @@ func (c *Command) Argument(id int) string {
 }
 
 // Flags returns the list of flags of this command.
+//
+// Persistent flags inherited from the parent commands are not included, see
+// InheritedFlags.
 func (c *Command) Flags() []*Flag {
 	return c.flags
 }
 
+// availableFlags returns the list of flags of this command, including the
+// inherited ones.
+func (c *Command) availableFlags() []*Flag {
+	flags := make([]*Flag, 0)
+	flags = append(flags, c.Flags()...)
+	flags = append(flags, c.InheritedFlags()...)
+
+	return flags
+}
+
+// pathFlags returns the list of flags that can be given on the command line
+// for this command: its available flags, followed by the local flags of its
+// parent commands, which are parsed in their part of the command line.
+func (c *Command) pathFlags() []*Flag {
+	flags := c.availableFlags()
+
+	seen := make(map[*Flag]bool, len(flags))
+	for _, flag := range flags {
+		seen[flag] = true
+	}
+
+	for command := c.parent; command != nil; command = command.parent {
+		for _, flag := range command.Flags() {
+			if !seen[flag] {
+				seen[flag] = true
+				flags = append(flags, flag)
+			}
+		}
+	}
+
+	return flags
+}
+
+// PersistentFlags returns the list of persistent flags of this command.
+func (c *Command) PersistentFlags() []*Flag {
+	flags := make([]*Flag, 0)
+	for _, flag := range c.flags {
+		if flag.Persistent {
+			flags = append(flags, flag)
+		}
+	}
+
+	return flags
+}
+
+// InheritedFlags returns the list of persistent flags this command inherits
+// from its parent commands.
+//
+// A persistent flag is not inherited if a flag with the same name is already
+// defined on this command or on a nearer parent.
+func (c *Command) InheritedFlags() []*Flag {
+	flags := make([]*Flag, 0)
+	for parent := c.parent; parent != nil; parent = parent.parent {
+		for _, flag := range parent.PersistentFlags() {
+			if c.localFlagName(flag.ShortName) != nil || c.localFlagName(flag.LongName) != nil {
+				continue
+			}
+			if shadowed(flags, flag) {
+				continue
+			}
+			flags = append(flags, flag)
+		}
+	}
+
+	return flags
+}
+
 // Flag returns a cli.Flag that represents a Flag of this command given a
 // numerical index.
 func (c *Command) Flag(id int) *Flag {
@@ -119,12 +422,29 @@ func (c *Command) Flag(id int) *Flag {
 
 // FlagName returns a cli.Flag that represents a Flag of this command given a
 // string name.
+//
+// The name can be given with or without its leading dashes. Persistent flags
+// inherited from the parent commands are also looked up.
 func (c *Command) FlagName(name string) *Flag {
-	for _, flag := range c.Flags() {
-		if flag.ShortName == name {
+	flag := c.localFlagName(name)
+	if flag != nil {
+		return flag
+	}
+
+	for _, flag := range c.InheritedFlags() {
+		if flag.matches(name) {
 			return flag
 		}
-		if flag.LongName == name {
+	}
+
+	return nil
+}
+
+// localFlagName returns the flag of this command given a string name, without
+// looking up inherited flags.
+func (c *Command) localFlagName(name string) *Flag {
+	for _, flag := range c.Flags() {
+		if flag.matches(name) {
 			return flag
 		}
 	}
@@ -157,10 +477,24 @@ func (c *Command) SetLogger(logger log.Logger) {
 	c.logger = logger
 }
 
+// Context returns the context this command is being executed with.
+//
+// It returns context.Background() when the command has not been executed.
+func (c *Command) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// SetArgs sets the command line arguments used when executing this command,
+// instead of os.Args[1:].
+func (c *Command) SetArgs(args []string) {
+	c.commandLine = args
+}
+
 // AddCommand adds a subCommand to this Command.
 func (c *Command) AddCommand(cmd *Command) {
-	c.arguments = os.Args[1:]
-
 	// Setup command default flag set
 	cmd.setupDefaultFlags()
 
@@ -168,22 +502,62 @@ func (c *Command) AddCommand(cmd *Command) {
 
 	cmd.SetLogger(c.Logger())
 
+	cmd.parent = c
+
 	c.commands = append(c.commands, cmd)
 }
 
+// Use adds middlewares that wrap the action of this command, and of every
+// sub-command of it.
+//
+// Middlewares of a parent command wrap the ones of its sub-commands, and the
+// middlewares of a command wrap the action in the order they were added, so
+// the first one added is the outermost.
+func (c *Command) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
 // AddFlag adds a flag to this Command.
 func (c *Command) AddFlag(flag *Flag) {
+	flag.owner = c
+	flag.initial = flag.Value
+	if flag.Var != nil {
+		flag.initial = flag.Var.String()
+	}
+
 	c.flags = append(c.flags, flag)
 }
 
+// AddPersistentFlag adds a persistent flag to this Command.
+//
+// A persistent flag is also parsed and visible on every sub-command of this
+// command.
+func (c *Command) AddPersistentFlag(flag *Flag) {
+	flag.Persistent = true
+
+	c.AddFlag(flag)
+}
+
 // execute executes the command.
 //
 // Execute uses the command arguments and run through the command tree finding
 // appropriate matches for commands and then corresponding flags.
-func (c *Command) execute() error {
+func (c *Command) execute(ctx context.Context, args []string) error {
+	if c.HandleSignals {
+		var stop context.CancelFunc
+		ctx, stop = SignalContext(ctx)
+		defer stop()
+	}
+
 	// Setup command default flag set
 	c.setupDefaultFlags()
 
+	// Reset the flags parsed by a previous execution
+	c.resetFlags()
+
+	// Setup the help command of the root command
+	c.setupHelpCommand()
+
 	if c.output == nil {
 		c.SetOutput(os.Stdout)
 	}
@@ -192,42 +566,218 @@ func (c *Command) execute() error {
 		c.logger = log.NewNoopLogger()
 	}
 
-	c.arguments = os.Args[1:]
+	// A subcommand that takes its arguments verbatim, as the completion one,
+	// runs without parsing them.
+	if len(args) > 0 {
+		command := c.commandName(args[0])
+		if command != nil && command.rawArgs {
+			command.arguments = args[1:]
+			command.ctx = ctx
+			return command.run(ctx)
+		}
+	}
 
 	// Parse commands ans subcommands from the cli, routing to the command it
 	// Will be selected for execution.
-	cmd := c.ParseCommands(c.Arguments())
-
-	// Parses flags and arguments for the selected command for execution.
-	cmd = cmd.ParseFlags(c.Arguments())
-
-	// If the special flags '-h', or '-help' are present on the current
-	// parsed flags execute the Usage() method for the command.
-	for _, flag := range cmd.Flags() {
-		if flag.ShortName == "-h" || flag.LongName == "-help" {
-			if flag.Parsed {
-				cmd.Usage()
-				return nil
+	cmd, err := c.ParseCommands(args)
+	if err != nil {
+		return c.parseError(err)
+	}
+
+	// Parses flags and arguments along the route to the selected command for
+	// execution, each flag by the command of the segment it is given in.
+	cmd, err = c.ParseFlags(args)
+	if err != nil {
+		return c.parseError(err)
+	}
+
+	// If the special flags '-h', or '-help' are present on the parsed flags
+	// of any command in the route execute the Usage() method for the
+	// selected command.
+	for _, command := range cmd.path() {
+		for _, flag := range command.Flags() {
+			if isHelpFlag(flag) && flag.Parsed {
+				return cmd.usage()
 			}
 		}
 	}
 
+	// Set the flags not given on the command line from the environment.
+	err = cmd.applyEnv()
+	if err != nil {
+		return c.parseError(err)
+	}
+
+	// Set the flags not given on the command line or from the environment
+	// from the configuration file.
+	err = cmd.applyConfig()
+	if err != nil {
+		return c.parseError(err)
+	}
+
+	// Warn about the deprecated commands and flags being used.
+	cmd.warnDeprecated()
+
+	// Set the flags not supplied from any source to their defaults.
+	err = cmd.applyDefaults()
+	if err != nil {
+		return c.parseError(err)
+	}
+
+	// Validate the flags of the selected command.
+	err = cmd.validateFlags()
+	if err != nil {
+		return c.parseError(err)
+	}
+
+	// Validate the positional arguments of the selected command.
+	err = cmd.validateArgs()
+	if err != nil {
+		return c.parseError(err)
+	}
+
+	// Make the context available along the path of the selected command.
+	for command := cmd; command != nil; command = command.parent {
+		command.ctx = ctx
+	}
+
+	err = cmd.run(ctx)
+
+	return err
+}
+
+// run runs the command action and its hooks.
+//
+// Hooks run in this order, and the first error aborts the execution:
+//
+//	PersistentPreRun of every command in the path, from the root
+//	PreRun
+//	Run or RunContext, wrapped by the middlewares
+//	PostRun
+//	PersistentPostRun of every command in the path, to the root
+func (c *Command) run(ctx context.Context) error {
+	path := c.path()
+
+	for _, command := range path {
+		if command.PersistentPreRun != nil {
+			err := command.PersistentPreRun(c)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.PreRun != nil {
+		err := c.PreRun(c)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Run the command action if it is runnable.
-	if cmd.Run != nil {
-		err := cmd.Run(cmd)
+	action := c.action(ctx)
+	if action != nil {
+		for i := len(path) - 1; i >= 0; i-- {
+			middlewares := path[i].middlewares
+			for j := len(middlewares) - 1; j >= 0; j-- {
+				action = middlewares[j](action)
+			}
+		}
+
+		err := action(c)
 		if err != nil {
 			return err
 		}
 	}
 
+	if c.PostRun != nil {
+		err := c.PostRun(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].PersistentPostRun != nil {
+			err := path[i].PersistentPostRun(c)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// action returns the command action, or nil if the command is not runnable.
+func (c *Command) action(ctx context.Context) RunFunc {
+	switch {
+	case c.RunContext != nil:
+		return func(c *Command) error {
+			return c.RunContext(ctx, c)
+		}
+	case c.Run != nil:
+		return c.Run
+	}
+
+	return nil
+}
+
+// CommandPath returns the full invocation path of this command, the names of
+// the commands from the root command to this command, space separated.
+// E.g. "programName subCommand1".
+func (c *Command) CommandPath() string {
+	names := make([]string, 0)
+	for _, command := range c.path() {
+		names = append(names, command.Name)
+	}
+
+	return strings.Join(names, " ")
+}
+
+// path returns the commands from the root command to this command.
+func (c *Command) path() []*Command {
+	path := make([]*Command, 0)
+	for command := c; command != nil; command = command.parent {
+		path = append([]*Command{command}, path...)
+	}
+
+	return path
+}
+
+// parseError handles an error found when parsing the command line, showing
+// the usage of the command related to it if requested.
+func (c *Command) parseError(err error) error {
+	var cmdErr commandError
+	if c.ShowUsageOnError && errors.As(err, &cmdErr) {
+		cmdErr.command().Usage()
+	}
+
+	return err
+}
+
+// resetFlags resets the flags of this command and of its sub-commands to
+// their state before being parsed, so the command can be executed again.
+func (c *Command) resetFlags() {
+	for _, flag := range c.flags {
+		flag.reset()
+	}
+
+	for _, command := range c.commands {
+		command.resetFlags()
+	}
+}
+
 // setupDefaultFlags adds default flags that all commands must support.
 //
 // Currently:
 //		-h, -help
 func (c *Command) setupDefaultFlags() {
+	// Default flags are already set up
+	if c.FlagName("-help") != nil {
+		return
+	}
+
 	// help Flag
 	helpFlag := &Flag{
 		ShortName:   "-h",
@@ -235,5 +785,5 @@ func (c *Command) setupDefaultFlags() {
 		Description: "Show help message",
 		Value:       "false",
 	}
-	c.flags = append(c.flags, helpFlag)
+	c.AddFlag(helpFlag)
 }
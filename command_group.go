@@ -0,0 +1,94 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+const (
+	// commandsTitle is the heading of the sub-commands in the usage output
+	// when none of them is grouped.
+	commandsTitle = "Commands"
+
+	// otherCommandsTitle is the heading of the ungrouped sub-commands in the
+	// usage output when some of them are grouped.
+	otherCommandsTitle = "Other Commands"
+)
+
+// SetCommandGroups sets the order the groups of the sub-commands of this
+// command are shown in the usage output, by their titles. See Command.Group.
+//
+// Groups not given are shown after the given ones, in the order of their first
+// sub-command, and the ungrouped sub-commands are shown last.
+func (c *Command) SetCommandGroups(titles ...string) {
+	c.commandGroups = titles
+}
+
+// CommandGroups returns the order of the groups of the sub-commands of this
+// command set with SetCommandGroups.
+func (c *Command) CommandGroups() []string {
+	return c.commandGroups
+}
+
+// usageCommandGroups returns the commands grouped as shown in the usage
+// output.
+func (c *Command) usageCommandGroups(commands []UsageCommand) []UsageCommandGroup {
+	titles := make([]string, 0)
+	titles = append(titles, c.commandGroups...)
+	for _, command := range commands {
+		if command.Group != "" && !containsString(titles, command.Group) {
+			titles = append(titles, command.Group)
+		}
+	}
+
+	groups := make([]UsageCommandGroup, 0)
+	for _, title := range titles {
+		group := UsageCommandGroup{Title: title}
+		for _, command := range commands {
+			if command.Group == title {
+				group.Commands = append(group.Commands, command)
+			}
+		}
+		if len(group.Commands) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	ungrouped := UsageCommandGroup{Title: commandsTitle}
+	if len(groups) > 0 {
+		ungrouped.Title = otherCommandsTitle
+	}
+	for _, command := range commands {
+		if command.Group == "" {
+			ungrouped.Commands = append(ungrouped.Commands, command)
+		}
+	}
+	if len(ungrouped.Commands) > 0 {
+		groups = append(groups, ungrouped)
+	}
+
+	return groups
+}
+
+// containsString checks if s is one of the elements of list.
+func containsString(list []string, s string) bool {
+	for _, element := range list {
+		if element == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_Usage_commandGroups(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetCommandGroups("Management", "Debugging")
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	commands := []struct {
+		name  string
+		group string
+	}{
+		{"logs", "Debugging"},
+		{"create", "Management"},
+		{"version", ""},
+		{"login", "Authentication"},
+		{"remove", "Management"},
+	}
+	for _, command := range commands {
+		subCommand := cli.NewCommand(command.name, command.name+" Description")
+		subCommand.Group = command.group
+		rootCommand.AddCommand(subCommand)
+	}
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-help"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := "Management:\n"
+	expected += "  create     create Description\n"
+	expected += "  remove     remove Description\n"
+	expected += "\n"
+	expected += "Debugging:\n"
+	expected += "  logs       logs Description\n"
+	expected += "\n"
+	expected += "Authentication:\n"
+	expected += "  login      login Description\n"
+	expected += "\n"
+	expected += "Other Commands:\n"
+	expected += "  version    version Description\n"
+	expected += "  help       Help about any command\n"
+	if !strings.Contains(buf.String(), expected) {
+		t.Fatalf("Expected %q in %q", expected, buf.String())
+	}
+}
@@ -19,71 +19,62 @@ package cli_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"os"
+	"reflect"
 	"testing"
 
 	"github.com/goombaio/cli"
 )
 
 func TestCommand(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_withoutConstructor(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := &cli.Command{
 		Name:             "programName",
 		ShortDescription: "rootCommand Description",
 		LongDescription:  "rootCommand Long Description",
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_Execute(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_withoutConstructor_Execute(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := &cli.Command{
 		Name:             "programName",
 		ShortDescription: "rootCommand Description",
 		LongDescription:  "rootCommand Long Description",
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_Name(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -91,15 +82,13 @@ func TestCommand_Name(t *testing.T) {
 		t.Fatalf("Expected %q but got %q", "programName", rootCommand.Name)
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_withoutConstructor_Name(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -107,15 +96,13 @@ func TestCommand_withoutConstructor_Name(t *testing.T) {
 		t.Fatalf("Expected %q but got %q", "programName", rootCommand.Name)
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_ShortDescription(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -123,15 +110,13 @@ func TestCommand_ShortDescription(t *testing.T) {
 		t.Fatalf("Expected %q but got %q", "rootCommand Description", rootCommand.ShortDescription)
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_withoutConstructor_ShortDescription(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := &cli.Command{
 		Name:             "programName",
 		ShortDescription: "rootCommand Description",
@@ -142,15 +127,13 @@ func TestCommand_withoutConstructor_ShortDescription(t *testing.T) {
 		t.Fatalf("Expected %q but got %q", "rootCommand Description", rootCommand.ShortDescription)
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_LongDescription(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -158,15 +141,13 @@ func TestCommand_LongDescription(t *testing.T) {
 		t.Fatalf("Expected %q but got %q", "rootCommand Long Description", rootCommand.LongDescription)
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_withoutConstructor_LongDescription(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := &cli.Command{
 		Name:             "programName",
 		ShortDescription: "rootCommand Description",
@@ -177,19 +158,17 @@ func TestCommand_withoutConstructor_LongDescription(t *testing.T) {
 		t.Fatalf("Expected %q but got %q", "rootCommand Long Description", rootCommand.LongDescription)
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 }
 
 func TestCommand_countCommands_countArguments_countFlags(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
@@ -208,8 +187,6 @@ func TestCommand_countCommands_countArguments_countFlags(t *testing.T) {
 }
 
 func TestCommand_withoutConstructor_countCommands_countArguments_countFlags(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := &cli.Command{
 		Name:             "programName",
 		ShortDescription: "rootCommand Description",
@@ -223,7 +200,7 @@ func TestCommand_withoutConstructor_countCommands_countArguments_countFlags(t *t
 
 	rootCommand.SetOutput(ioutil.Discard)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
@@ -241,9 +218,30 @@ func TestCommand_withoutConstructor_countCommands_countArguments_countFlags(t *t
 	}
 }
 
-func TestCommand_Commands(t *testing.T) {
-	os.Args = []string{"programName"}
+func TestCommand_CommandPath(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand2 := cli.NewCommand("subCommand2", "subCommand2 Description")
+	subCommand1.AddCommand(subCommand2)
+	rootCommand.AddCommand(subCommand1)
+
+	testCases := []struct {
+		command  *cli.Command
+		expected string
+	}{
+		{rootCommand, "programName"},
+		{subCommand1, "programName subCommand1"},
+		{subCommand2, "programName subCommand1 subCommand2"},
+	}
 
+	for _, tc := range testCases {
+		if tc.command.CommandPath() != tc.expected {
+			t.Fatalf("Expected %q but got %q", tc.expected, tc.command.CommandPath())
+		}
+	}
+}
+
+func TestCommand_Commands(t *testing.T) {
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -257,8 +255,6 @@ func TestCommand_Commands(t *testing.T) {
 }
 
 func TestCommand_Command(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -280,8 +276,6 @@ func TestCommand_Command(t *testing.T) {
 }
 
 func TestCommand_Arguments(t *testing.T) {
-	os.Args = []string{"programName", "argument1"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -289,14 +283,21 @@ func TestCommand_Arguments(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	if len(rootCommand.Arguments()) != 1 {
-		t.Fatalf("Expected 1 arguments but got %d", len(rootCommand.Arguments()))
+	if len(rootCommand.Arguments()) != 0 {
+		t.Fatalf("Expected 0 arguments but got %d", len(rootCommand.Arguments()))
+	}
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1", "argument1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if len(subCommand1.Arguments()) != 1 {
+		t.Fatalf("Expected 1 arguments but got %d", len(subCommand1.Arguments()))
 	}
 }
 
 func TestCommand_Argument(t *testing.T) {
-	os.Args = []string{"programName", "argument1"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -304,14 +305,19 @@ func TestCommand_Argument(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	if rootCommand.Argument(0) != "argument1" {
-		t.Fatalf("Expected argument1 arguments but got %d", len(rootCommand.Argument(0)))
+	rootCommand.SetArgs([]string{"subCommand1", "argument1"})
+
+	err := cli.Execute(rootCommand)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if subCommand1.Argument(0) != "argument1" {
+		t.Fatalf("Expected argument1 arguments but got %s", subCommand1.Argument(0))
 	}
 }
 
 func TestCommand_Flags(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -321,7 +327,7 @@ func TestCommand_Flags(t *testing.T) {
 
 	rootCommand.SetOutput(ioutil.Discard)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
@@ -332,8 +338,6 @@ func TestCommand_Flags(t *testing.T) {
 }
 
 func TestCommand_Flag(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 
@@ -343,7 +347,7 @@ func TestCommand_Flag(t *testing.T) {
 
 	rootCommand.SetOutput(ioutil.Discard)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
@@ -366,8 +370,6 @@ func TestCommand_Flag(t *testing.T) {
 }
 
 func TestCommand_SetOutput(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -378,7 +380,7 @@ func TestCommand_SetOutput(t *testing.T) {
 	buf := new(bytes.Buffer)
 	rootCommand.SetOutput(buf)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
@@ -390,8 +392,6 @@ func TestCommand_SetOutput(t *testing.T) {
 }
 
 func TestCommand_Output(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -408,7 +408,7 @@ func TestCommand_Output(t *testing.T) {
 		t.Fatalf("Expected %#v but got %#v", buf, output)
 	}
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
@@ -418,3 +418,287 @@ func TestCommand_Output(t *testing.T) {
 		t.Fatalf("Expected %q but got %q", expected, buf.String())
 	}
 }
+
+func TestCommand_PersistentFlags(t *testing.T) {
+	testCases := [][]string{
+		{"-verbose", "subCommand1", "subCommand2"},
+		{"subCommand1", "-verbose", "subCommand2"},
+		{"subCommand1", "subCommand2", "-verbose"},
+	}
+
+	for _, args := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		verbose := rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+		rootCommand.FlagName("-verbose").Persistent = true
+
+		subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+		rootCommand.AddCommand(subCommand1)
+
+		subCommand2 := cli.NewCommand("subCommand2", "subCommand2 Description")
+		subCommand2.Run = func(c *cli.Command) error {
+			v, err := c.GetBool("-verbose")
+			if err != nil {
+				return err
+			}
+			if !v {
+				t.Fatalf("Expected -verbose to be visible on %s for %q", c.Name, args)
+			}
+
+			return nil
+		}
+		subCommand1.AddCommand(subCommand2)
+
+		err := cli.ExecuteArgs(rootCommand, args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		if !*verbose {
+			t.Fatalf("Expected %v but got %v for %q", true, *verbose, args)
+		}
+	}
+}
+
+func TestCommand_InheritedFlags(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddPersistentFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file"})
+	rootCommand.AddPersistentFlag(&cli.Flag{ShortName: "-o", LongName: "-output", Description: "Output format"})
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-l", LongName: "-local", Description: "Local flag"})
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.AddFlag(&cli.Flag{ShortName: "-o", LongName: "-output", Description: "Shadowing flag"})
+	rootCommand.AddCommand(subCommand1)
+
+	if len(rootCommand.PersistentFlags()) != 2 {
+		t.Fatalf("Expected 2 persistent flags but got %d", len(rootCommand.PersistentFlags()))
+	}
+
+	if len(rootCommand.InheritedFlags()) != 0 {
+		t.Fatalf("Expected 0 inherited flags but got %d", len(rootCommand.InheritedFlags()))
+	}
+
+	inherited := subCommand1.InheritedFlags()
+	if len(inherited) != 1 || inherited[0].LongName != "-config" {
+		t.Fatalf("Expected [-config] inherited flags but got %d flags", len(inherited))
+	}
+
+	if subCommand1.FlagName("-local") != nil {
+		t.Fatalf("Expected -local not to be visible on %s", subCommand1.Name)
+	}
+
+	if subCommand1.FlagName("-output").Description != "Shadowing flag" {
+		t.Fatalf("Expected %q but got %q", "Shadowing flag", subCommand1.FlagName("-output").Description)
+	}
+
+	if subCommand1.Parent() != rootCommand {
+		t.Fatalf("Expected parent %s", rootCommand.Name)
+	}
+}
+
+func TestCommand_hooks(t *testing.T) {
+	calls := make([]string, 0)
+	hook := func(name string) func(c *cli.Command) error {
+		return func(c *cli.Command) error {
+			calls = append(calls, name+":"+c.Name)
+
+			return nil
+		}
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.PersistentPreRun = hook("rootPersistentPreRun")
+	rootCommand.PreRun = hook("rootPreRun")
+	rootCommand.PostRun = hook("rootPostRun")
+	rootCommand.PersistentPostRun = hook("rootPersistentPostRun")
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.PersistentPreRun = hook("subCommand1PersistentPreRun")
+	subCommand1.PersistentPostRun = hook("subCommand1PersistentPostRun")
+	rootCommand.AddCommand(subCommand1)
+
+	subCommand2 := cli.NewCommand("subCommand2", "subCommand2 Description")
+	subCommand2.PreRun = hook("subCommand2PreRun")
+	subCommand2.Run = hook("subCommand2Run")
+	subCommand2.PostRun = hook("subCommand2PostRun")
+	subCommand1.AddCommand(subCommand2)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1", "subCommand2"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := []string{
+		"rootPersistentPreRun:subCommand2",
+		"subCommand1PersistentPreRun:subCommand2",
+		"subCommand2PreRun:subCommand2",
+		"subCommand2Run:subCommand2",
+		"subCommand2PostRun:subCommand2",
+		"subCommand1PersistentPostRun:subCommand2",
+		"rootPersistentPostRun:subCommand2",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("Expected %q but got %q", expected, calls)
+	}
+}
+
+func TestCommand_hooks_error(t *testing.T) {
+	hookErr := errors.New("unauthorized")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.PersistentPreRun = func(c *cli.Command) error {
+		return hookErr
+	}
+	rootCommand.PersistentPostRun = func(c *cli.Command) error {
+		t.Fatalf("Expected PersistentPostRun not to be called")
+
+		return nil
+	}
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.Run = func(c *cli.Command) error {
+		t.Fatalf("Expected Run not to be called")
+
+		return nil
+	}
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1"})
+	if err != hookErr {
+		t.Fatalf("Expected %s but got %v", hookErr, err)
+	}
+}
+
+func TestCommand_Use(t *testing.T) {
+	calls := make([]string, 0)
+	middleware := func(name string) cli.Middleware {
+		return func(next cli.RunFunc) cli.RunFunc {
+			return func(c *cli.Command) error {
+				calls = append(calls, "before:"+name)
+				err := next(c)
+				calls = append(calls, "after:"+name)
+
+				return err
+			}
+		}
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Use(middleware("root1"), middleware("root2"))
+	rootCommand.PreRun = func(c *cli.Command) error {
+		t.Fatalf("Expected root PreRun not to be called")
+
+		return nil
+	}
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.Use(middleware("subCommand1"))
+	subCommand1.PreRun = func(c *cli.Command) error {
+		calls = append(calls, "preRun")
+
+		return nil
+	}
+	subCommand1.Run = func(c *cli.Command) error {
+		calls = append(calls, "run")
+
+		return nil
+	}
+	subCommand1.PostRun = func(c *cli.Command) error {
+		calls = append(calls, "postRun")
+
+		return nil
+	}
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := []string{
+		"preRun",
+		"before:root1",
+		"before:root2",
+		"before:subCommand1",
+		"run",
+		"after:subCommand1",
+		"after:root2",
+		"after:root1",
+		"postRun",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("Expected %q but got %q", expected, calls)
+	}
+}
+
+func TestCommand_Use_recover(t *testing.T) {
+	recoverer := func(next cli.RunFunc) cli.RunFunc {
+		return func(c *cli.Command) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%s panicked: %v", c.Name, r)
+				}
+			}()
+
+			return next(c)
+		}
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Use(recoverer)
+	rootCommand.Run = func(c *cli.Command) error {
+		panic("boom")
+	}
+
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	if err == nil || err.Error() != "programName panicked: boom" {
+		t.Fatalf("Expected %q but got %v", "programName panicked: boom", err)
+	}
+}
+
+func TestCommand_Execute_twice(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(buf)
+	verbose := rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+	tags := rootCommand.StringSlice("-t", "-tag", nil, "Tags")
+	name := rootCommand.String("-n", "-name", "", "Name")
+	rootCommand.FlagName("-name").EnvVars = []string{"PROGRAMNAME_NAME"}
+	runs := 0
+	rootCommand.Run = func(c *cli.Command) error {
+		runs++
+		return nil
+	}
+
+	t.Setenv("PROGRAMNAME_NAME", "first")
+	err := cli.ExecuteArgs(rootCommand, []string{"-h"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if runs != 0 {
+		t.Fatalf("Expected %d runs but got %d", 0, runs)
+	}
+
+	err = cli.ExecuteArgs(rootCommand, []string{"-v", "-t", "x"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if runs != 1 {
+		t.Fatalf("Expected %d runs but got %d", 1, runs)
+	}
+	if !*verbose || fmt.Sprint(*tags) != "[x]" || *name != "first" {
+		t.Fatalf("Expected %v %q %q but got %v %q %q", true, "[x]", "first", *verbose, fmt.Sprint(*tags), *name)
+	}
+
+	t.Setenv("PROGRAMNAME_NAME", "second")
+	err = cli.ExecuteArgs(rootCommand, []string{"-t", "y"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if runs != 2 {
+		t.Fatalf("Expected %d runs but got %d", 2, runs)
+	}
+	if *verbose || fmt.Sprint(*tags) != "[y]" || *name != "second" {
+		t.Fatalf("Expected %v %q %q but got %v %q %q", false, "[y]", "second", *verbose, fmt.Sprint(*tags), *name)
+	}
+}
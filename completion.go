@@ -0,0 +1,363 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+const (
+	// completeCommandName is the name of the hidden command that serves the
+	// completion candidates to the shell completion scripts.
+	completeCommandName = "__complete"
+
+	// bashCompletionTemplate is the template of the bash completion script.
+	bashCompletionTemplate = `# bash completion for {{.Name}}
+#
+# Load it in the current shell with:
+#   source <({{.Name}} completion bash)
+
+{{.Function}}() {
+    local line cur candidate value
+    local -a words
+    COMPREPLY=()
+
+    line="${COMP_LINE:0:COMP_POINT}"
+    read -r -a words <<< "${line}"
+    if [[ "${line}" == *" " || ${#words[@]} -eq 1 ]]; then
+        words+=( "" )
+    fi
+    cur="${words[${#words[@]}-1]}"
+
+    while IFS= read -r candidate; do
+        value="${candidate%%$'\t'*}"
+        # The current word was split at '=' by bash
+        if [[ "${cur}" == *=* && "${COMP_WORDBREAKS}" == *=* ]]; then
+            value="${value#*=}"
+        fi
+        COMPREPLY+=( "${value}" )
+    done < <("${words[0]}" {{.Complete}} "${words[@]:1}" 2>/dev/null)
+}
+
+complete -o default -F {{.Function}} {{.Name}}
+`
+
+	// zshCompletionTemplate is the template of the zsh completion script.
+	zshCompletionTemplate = `#compdef {{.Name}}
+#
+# Load it in the current shell with:
+#   source <({{.Name}} completion zsh)
+
+{{.Function}}() {
+    local candidate value description
+    local -a candidates
+
+    for candidate in "${(@f)$("${words[1]}" {{.Complete}} "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z "${candidate}" ]] && continue
+        value="${candidate%%$'\t'*}"
+        description="${candidate#*$'\t'}"
+        [[ "${description}" == "${candidate}" ]] && description=""
+        candidates+=( "${value//:/\\:}${description:+:${description}}" )
+    done
+
+    if (( ${#candidates} )); then
+        _describe -t values '{{.Name}}' candidates
+    else
+        _files
+    fi
+}
+
+compdef {{.Function}} {{.Name}}
+`
+
+	// fishCompletionTemplate is the template of the fish completion script.
+	fishCompletionTemplate = `# fish completion for {{.Name}}
+#
+# Load it in the current shell with:
+#   {{.Name}} completion fish | source
+
+function {{.Function}}
+    set -l words (commandline -opc)
+    command $words[1] {{.Complete}} $words[2..-1] (commandline -ct) 2>/dev/null
+end
+
+complete -c {{.Name}} -f -a '({{.Function}})'
+`
+
+	// powerShellCompletionTemplate is the template of the PowerShell
+	// completion script.
+	powerShellCompletionTemplate = `# PowerShell completion for {{.Name}}
+#
+# Load it in the current shell with:
+#   {{.Name}} completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName {{ps .Name}} -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $program = $commandAst.CommandElements[0].ToString()
+    $arguments = @($commandAst.CommandElements | Select-Object -Skip 1 |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } |
+        ForEach-Object { $_.ToString() })
+
+    # Empty arguments are dropped by older versions when calling a program
+    if ($wordToComplete -eq '' -and $PSVersionTable.PSVersion -lt [version]'7.3') {
+        $arguments += '""'
+    } else {
+        $arguments += $wordToComplete
+    }
+
+    & $program {{.Complete}} @arguments 2>$null | ForEach-Object {
+        $value, $description = $_ -split "` + "`" + `t", 2
+        if (-not $description) {
+            $description = $value
+        }
+        [System.Management.Automation.CompletionResult]::new($value, $value, 'ParameterValue', $description)
+    }
+}
+`
+)
+
+// Completion is a completion candidate.
+type Completion struct {
+	// Value is the text the word being completed is replaced with.
+	Value string
+
+	// Description is the message shown along the value, by the shells that
+	// support it.
+	Description string
+}
+
+// CompletionFunc returns the completion candidates for the value of a flag or
+// of a positional argument that starts with prefix.
+//
+// The command is the command selected for execution by the words before the
+// one being completed, with the flags given on them already parsed.
+type CompletionFunc func(c *Command, prefix string) []Completion
+
+// completions returns the completion candidates for the last of the
+// arguments, given the arguments that precede it.
+func (c *Command) completions(args []string) []Completion {
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	cmd, err := c.ParseCommands(args)
+	if err != nil {
+		return nil
+	}
+
+	// The flags are parsed as far as possible, so the completion functions
+	// can use their values.
+	_, _ = c.ParseFlags(args)
+	_ = cmd.applyEnv()
+	_ = cmd.applyDefaults()
+
+	// The value of a flag given as a separate argument
+	if len(args) > 0 && IsFlag(args[len(args)-1]) && cmd.flagTakesValue(args[len(args)-1]) {
+		flag := cmd.valueFlag(args[len(args)-1])
+		if flag == nil || flag.Complete == nil {
+			return nil
+		}
+		return flag.Complete(cmd, prefix)
+	}
+
+	if IsFlag(prefix) || prefix == "-" {
+		name, value, hasValue := splitFlag(prefix)
+
+		// The value of a flag given in the `=` separated form
+		if hasValue {
+			flag := cmd.valueFlag(name)
+			if flag == nil || flag.Complete == nil {
+				return nil
+			}
+
+			completions := flag.Complete(cmd, value)
+			for i := range completions {
+				completions[i].Value = name + "=" + completions[i].Value
+			}
+			return completions
+		}
+
+		completions := make([]Completion, 0)
+		for _, flag := range cmd.availableFlags() {
+			if !flag.visible() {
+				continue
+			}
+			for i, name := range []string{flag.ShortName, flag.LongName} {
+				if cmd.gnuFlags() {
+					name = gnuFlagName(name, i == 1)
+				}
+				if trimDashes(name) != "" && strings.HasPrefix(name, prefix) {
+					completions = append(completions, Completion{name, flag.Description})
+				}
+			}
+		}
+		return completions
+	}
+
+	completions := make([]Completion, 0)
+
+	// Sub-commands are matched until the first positional argument
+	if len(cmd.arguments) == 0 {
+		for _, command := range cmd.commands {
+			if command.visible() && strings.HasPrefix(command.Name, prefix) {
+				completions = append(completions, Completion{command.Name, command.ShortDescription})
+			}
+		}
+	}
+
+	arg := cmd.positional(len(cmd.arguments))
+	if arg != nil && arg.Complete != nil {
+		completions = append(completions, arg.Complete(cmd, prefix)...)
+	}
+
+	return completions
+}
+
+// genCompletion writes the completion script rendered from tmpl for this
+// command.
+func (c *Command) genCompletion(w io.Writer, tmpl string) error {
+	function := "_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, c.Name) + "_completion"
+
+	templateData := struct {
+		Name     string
+		Function string
+		Complete string
+	}{
+		Name:     c.Name,
+		Function: function,
+		Complete: completeCommandName,
+	}
+
+	funcs := template.FuncMap{
+		"ps": quotePowerShell,
+	}
+
+	t := template.Must(template.New("completionTemplate").Funcs(funcs).Parse(tmpl))
+
+	return t.Execute(w, templateData)
+}
+
+// GenBashCompletion writes the bash completion script for this command.
+//
+// The completion scripts get the completion candidates from the program,
+// using the hidden sub-command added by AddCompletionCommand.
+func (c *Command) GenBashCompletion(w io.Writer) error {
+	return c.genCompletion(w, bashCompletionTemplate)
+}
+
+// GenZshCompletion writes the zsh completion script for this command.
+func (c *Command) GenZshCompletion(w io.Writer) error {
+	return c.genCompletion(w, zshCompletionTemplate)
+}
+
+// GenFishCompletion writes the fish completion script for this command.
+func (c *Command) GenFishCompletion(w io.Writer) error {
+	return c.genCompletion(w, fishCompletionTemplate)
+}
+
+// GenPowerShellCompletion writes the PowerShell completion script for this
+// command.
+func (c *Command) GenPowerShellCompletion(w io.Writer) error {
+	return c.genCompletion(w, powerShellCompletionTemplate)
+}
+
+// AddCompletionCommand adds the sub-command "completion <shell>" to this
+// command, which writes the completion script for the given shell to the
+// command output.
+//
+// Supported shells are bash, zsh, fish and powershell.
+//
+// It also adds the hidden sub-command "__complete", which the completion
+// scripts call with the words of the command line to get the candidates for
+// the last one, written one per line as the value and the description
+// separated by a tab.
+func (c *Command) AddCompletionCommand() {
+	completionCommand := NewCommand("completion", "Generate shell completion scripts")
+	completionCommand.LongDescription = "Generate the completion script of " + c.Name +
+		" for the given shell (bash, zsh, fish or powershell)."
+	completionCommand.AddArg(&Arg{
+		Name:        "shell",
+		Description: "Shell to generate the script for",
+		Required:    true,
+		Complete: func(cmd *Command, prefix string) []Completion {
+			completions := make([]Completion, 0)
+			for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+				if strings.HasPrefix(shell, prefix) {
+					completions = append(completions, Completion{Value: shell})
+				}
+			}
+			return completions
+		},
+	})
+	completionCommand.Run = func(cmd *Command) error {
+		shell := cmd.Argument(0)
+
+		switch shell {
+		case "bash":
+			return c.GenBashCompletion(c.Output())
+		case "zsh":
+			return c.GenZshCompletion(c.Output())
+		case "fish":
+			return c.GenFishCompletion(c.Output())
+		case "powershell":
+			return c.GenPowerShellCompletion(c.Output())
+		}
+
+		return fmt.Errorf("unsupported shell %q, use one of bash, zsh, fish or powershell", shell)
+	}
+	c.AddCommand(completionCommand)
+
+	completeCommand := NewCommand(completeCommandName, "Complete a command line")
+	completeCommand.Hidden = true
+	completeCommand.rawArgs = true
+	completeCommand.Run = func(cmd *Command) error {
+		for _, completion := range c.completions(cmd.Arguments()) {
+			if completion.Description == "" {
+				fmt.Fprintln(c.Output(), completion.Value)
+				continue
+			}
+			fmt.Fprintf(c.Output(), "%s\t%s\n", completion.Value, firstLine(completion.Description))
+		}
+		return nil
+	}
+	c.AddCommand(completeCommand)
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// quotePowerShell quotes a string for PowerShell using single quotes.
+func quotePowerShell(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
@@ -0,0 +1,196 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_AddCompletionCommand(t *testing.T) {
+	testCases := []struct {
+		shell    string
+		expected []string
+	}{
+		{"bash", []string{
+			"_programName_completion() {",
+			`"${words[0]}" __complete "${words[@]:1}"`,
+			"complete -o default -F _programName_completion programName",
+		}},
+		{"zsh", []string{
+			"#compdef programName",
+			`"${words[1]}" __complete "${(@)words[2,CURRENT]}"`,
+			"compdef _programName_completion programName",
+		}},
+		{"fish", []string{
+			"command $words[1] __complete $words[2..-1] (commandline -ct)",
+			"complete -c programName -f -a '(_programName_completion)'",
+		}},
+		{"powershell", []string{
+			"Register-ArgumentCompleter -Native -CommandName 'programName'",
+			"& $program __complete @arguments",
+		}},
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddCompletionCommand()
+
+	output := new(bytes.Buffer)
+	rootCommand.SetOutput(output)
+
+	for _, tc := range testCases {
+		output.Reset()
+
+		err := cli.ExecuteArgs(rootCommand, []string{"completion", tc.shell})
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %s", err, tc.shell)
+		}
+
+		for _, expected := range tc.expected {
+			if !strings.Contains(output.String(), expected) {
+				t.Fatalf("Expected %q in %s script but got %q", expected, tc.shell, output.String())
+			}
+		}
+	}
+}
+
+func TestCommand_AddCompletionCommand_unsupportedShell(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddCompletionCommand()
+	rootCommand.SetOutput(new(bytes.Buffer))
+
+	err := cli.ExecuteArgs(rootCommand, []string{"completion", "tcsh"})
+	if err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+
+	expected := `unsupported shell "tcsh", use one of bash, zsh, fish or powershell`
+	if err.Error() != expected {
+		t.Fatalf("Expected %q but got %q", expected, err.Error())
+	}
+}
+
+func TestCommand_complete(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{""}, "serve\tServe Description\ncompletion\tGenerate shell completion scripts\nhelp\tHelp about any command\n"},
+		{[]string{"s"}, "serve\tServe Description\n"},
+		{[]string{"serve", "-c"}, "-c\tCluster\n-cluster\tCluster\n"},
+		{[]string{"serve", "-v", "-c", "s"}, "staging\tCluster staging\n"},
+		{[]string{"serve", "--cluster", ""}, "production\tCluster production\nstaging\tCluster staging\n"},
+		{[]string{"serve", "-cluster=p"}, "-cluster=production\tCluster production\n"},
+		{[]string{"serve", "-c", "staging", "a", "b"}, "ab.txt\n"},
+		{[]string{"completion", "z"}, "zsh\n"},
+		{[]string{"unknown", ""}, ""},
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddPersistentFlag(&cli.Flag{ShortName: "-v", LongName: "-verbose", Description: "Verbose output", Value: "false"})
+
+	serveCommand := cli.NewCommand("serve", "Serve Description")
+	serveCommand.AddFlag(&cli.Flag{ShortName: "-c", LongName: "-cluster", Description: "Cluster", Complete: func(c *cli.Command, prefix string) []cli.Completion {
+		completions := make([]cli.Completion, 0)
+		for _, cluster := range []string{"production", "staging"} {
+			if strings.HasPrefix(cluster, prefix) {
+				completions = append(completions, cli.Completion{Value: cluster, Description: "Cluster " + cluster})
+			}
+		}
+		return completions
+	}})
+	serveCommand.AddArg(&cli.Arg{Name: "files", Variadic: true, Complete: func(c *cli.Command, prefix string) []cli.Completion {
+		return []cli.Completion{{Value: c.Argument(0) + prefix + ".txt"}}
+	}})
+	rootCommand.AddCommand(serveCommand)
+	rootCommand.AddCompletionCommand()
+
+	output := new(bytes.Buffer)
+	rootCommand.SetOutput(output)
+
+	for _, tc := range testCases {
+		output.Reset()
+
+		err := cli.ExecuteArgs(rootCommand, append([]string{"__complete"}, tc.args...))
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+
+		if output.String() != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, output.String(), tc.args)
+		}
+	}
+}
+
+func TestCommand_complete_hidden(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddCompletionCommand()
+
+	output := new(bytes.Buffer)
+	rootCommand.SetOutput(output)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-help"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if strings.Contains(output.String(), "__complete") {
+		t.Fatalf("Expected no __complete command in %q", output.String())
+	}
+}
+
+func TestCommand_complete_GNUFlags(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-xo", ""}, "out.txt\n"},
+		{[]string{"-x", "-o", ""}, "out.txt\n"},
+		{[]string{"--output", ""}, "out.txt\n"},
+		{[]string{"-xv", ""}, "completion\tGenerate shell completion scripts\nhelp\tHelp about any command\n"},
+		{[]string{"-xo=o"}, "-xo=out.txt\n"},
+		{[]string{"--out"}, "--output\tOutput file\n"},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.GNUFlags = true
+		rootCommand.Bool("-x", "-extract", false, "Extract files")
+		rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+		flag := rootCommand.StringVar(new(string), "-o", "-output", "", "Output file")
+		flag.Complete = func(c *cli.Command, prefix string) []cli.Completion {
+			return []cli.Completion{{Value: "out.txt"}}
+		}
+		rootCommand.AddCompletionCommand()
+
+		output := new(bytes.Buffer)
+		rootCommand.SetOutput(output)
+
+		err := cli.ExecuteArgs(rootCommand, append([]string{"__complete"}, tc.args...))
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+
+		if output.String() != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, output.String(), tc.args)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ConfigLoader decodes a configuration file into a map of keys and values.
+//
+// Values can be strings, booleans, numbers, slices of values, or nested maps.
+// A nested map is a section that holds the keys for the sub-command with the
+// same name, or the pairs of a map flag.
+type ConfigLoader func(r io.Reader) (map[string]interface{}, error)
+
+var (
+	configLoadersMu sync.RWMutex
+	configLoaders   = map[string]ConfigLoader{
+		".json": LoadJSONConfig,
+		".ini":  LoadINIConfig,
+		".toml": LoadINIConfig,
+	}
+)
+
+// RegisterConfigLoader registers the loader for the configuration files with
+// the given extension, like ".yaml". It replaces any loader already registered
+// for the extension.
+//
+// JSON (.json) and a simple INI/TOML subset (.ini, .toml) are supported out of
+// the box.
+func RegisterConfigLoader(ext string, loader ConfigLoader) {
+	configLoadersMu.Lock()
+	defer configLoadersMu.Unlock()
+
+	configLoaders[strings.ToLower(ext)] = loader
+}
+
+// configLoader returns the loader registered for the extension of path.
+func configLoader(path string) (ConfigLoader, error) {
+	configLoadersMu.RLock()
+	defer configLoadersMu.RUnlock()
+
+	ext := strings.ToLower(filepath.Ext(path))
+
+	loader, ok := configLoaders[ext]
+	if !ok {
+		return nil, fmt.Errorf("no config loader registered for extension %q", ext)
+	}
+
+	return loader, nil
+}
+
+// AddConfigFlag adds a persistent flag to this Command whose value is the path
+// of a configuration file.
+//
+// When a command is executed the keys of the file set the flags of the
+// command that were not given on the command line or from the environment.
+// Keys at the top level of the file are the flags of this command, and
+// nested sections are the flags of the sub-commands with the same name.
+// Flags are matched by their long or short name without leading dashes.
+//
+// The file format is given by the file extension, see RegisterConfigLoader. A
+// missing file is ignored when its path is the default value of the flag.
+func (c *Command) AddConfigFlag(flag *Flag) {
+	c.configFlag = flag
+
+	c.AddPersistentFlag(flag)
+}
+
+// applyConfig sets the flags of this command, including the ones of its parent
+// commands, that were not given on the command line or from the environment
+// from the configuration file.
+func (c *Command) applyConfig() error {
+	var owner *Command
+	for command := c; command != nil; command = command.parent {
+		if command.configFlag != nil {
+			owner = command
+			break
+		}
+	}
+	if owner == nil {
+		return nil
+	}
+
+	// The defaults are applied after the configuration file is loaded
+	path := owner.configFlag.Value
+	if owner.configFlag.source == sourceDefault && owner.configFlag.Default != "" {
+		path = owner.configFlag.Default
+	}
+	if path == "" {
+		return nil
+	}
+
+	config, err := loadConfigFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && owner.configFlag.source == sourceDefault {
+			return nil
+		}
+		return &ConfigError{Command: c, Path: path, Err: err}
+	}
+
+	for _, flag := range c.pathFlags() {
+		if flag.source >= sourceConfig || flag == owner.configFlag {
+			continue
+		}
+
+		value, ok := configValue(config, owner, flag)
+		if !ok {
+			continue
+		}
+
+		values, err := configStrings(value)
+		if err != nil {
+			return &ConfigError{Command: c, Path: path, Err: fmt.Errorf("key %s: %v", trimDashes(flagDisplayName(flag)), err)}
+		}
+
+		for _, v := range values {
+			err := flag.Set(v)
+			if err != nil {
+				return &InvalidFlagValueError{
+					Command: flag.owner,
+					Flag:    flagDisplayName(flag),
+					Value:   v,
+					Err:     fmt.Errorf("from config file %s: %w", path, err),
+				}
+			}
+		}
+		flag.source = sourceConfig
+	}
+
+	return nil
+}
+
+// loadConfigFile loads the configuration file with the loader registered for
+// its extension.
+func loadConfigFile(path string) (map[string]interface{}, error) {
+	loader, err := configLoader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return loader(f)
+}
+
+// configValue returns the value of a flag from the configuration, looking it
+// up in the section of the command the flag belongs to.
+func configValue(config map[string]interface{}, owner *Command, flag *Flag) (interface{}, bool) {
+	sections := make([]string, 0)
+	command := flag.owner
+	for ; command != nil && command != owner; command = command.parent {
+		sections = append([]string{command.Name}, sections...)
+	}
+	if command != owner {
+		return nil, false
+	}
+
+	section := config
+	for _, name := range sections {
+		next, ok := section[name].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		section = next
+	}
+
+	for _, name := range []string{flag.LongName, flag.ShortName} {
+		name = trimDashes(name)
+		if name == "" {
+			continue
+		}
+		if value, ok := section[name]; ok {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// configStrings converts a configuration value into the string values to set
+// on a flag.
+func configStrings(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := configStrings(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s...)
+		}
+		return values, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		values := make([]string, 0, len(keys))
+		for _, k := range keys {
+			s, err := configStrings(v[k])
+			if err != nil {
+				return nil, err
+			}
+			if len(s) != 1 {
+				return nil, fmt.Errorf("invalid value for %q", k)
+			}
+			values = append(values, k+"="+s[0])
+		}
+		return values, nil
+	case nil:
+		return nil, fmt.Errorf("null value")
+	}
+
+	return []string{fmt.Sprint(value)}, nil
+}
+
+// LoadJSONConfig is the ConfigLoader for JSON files.
+func LoadJSONConfig(r io.Reader) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+
+	err := json.NewDecoder(r).Decode(&config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadINIConfig is the ConfigLoader for a simple subset of the INI and TOML
+// formats.
+//
+// It supports `[section]` and `[section.subsection]` headers, `key = value`
+// pairs, comments starting with '#' or ';', double quoted strings with
+// escapes, single quoted literal strings and single line arrays.
+func LoadINIConfig(r io.Reader) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	section := config
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid section header %q", n, line)
+			}
+
+			section = config
+			for _, name := range strings.Split(line[1:len(line)-1], ".") {
+				name = unquoteKey(strings.TrimSpace(name))
+				next, ok := section[name].(map[string]interface{})
+				if !ok {
+					next = make(map[string]interface{})
+					section[name] = next
+				}
+				section = next
+			}
+			continue
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value but got %q", n, line)
+		}
+
+		key := unquoteKey(strings.TrimSpace(line[:i]))
+		value, err := parseINIValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		section[key] = value
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// parseINIValue parses a value of an INI or TOML file.
+func parseINIValue(value string) (interface{}, error) {
+	if strings.HasPrefix(value, "[") {
+		value = stripComment(value)
+		if !strings.HasSuffix(value, "]") {
+			return nil, fmt.Errorf("unterminated array %q", value)
+		}
+
+		items := make([]interface{}, 0)
+		for _, item := range splitINIArray(value[1 : len(value)-1]) {
+			v, err := parseINIValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		end := closingQuote(value, '"')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string %q", value)
+		}
+		return strconv.Unquote(value[:end+1])
+	}
+
+	if strings.HasPrefix(value, "'") {
+		end := closingQuote(value, '\'')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string %q", value)
+		}
+		return value[1:end], nil
+	}
+
+	return stripComment(value), nil
+}
+
+// splitINIArray splits the items of an array, ignoring the commas inside
+// quoted strings.
+func splitINIArray(value string) []string {
+	items := make([]string, 0)
+
+	start := 0
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		switch {
+		case quote != 0:
+			if value[i] == '\\' && quote == '"' {
+				i++
+			} else if value[i] == quote {
+				quote = 0
+			}
+		case value[i] == '"' || value[i] == '\'':
+			quote = value[i]
+		case value[i] == ',':
+			items = append(items, strings.TrimSpace(value[start:i]))
+			start = i + 1
+		}
+	}
+
+	last := strings.TrimSpace(value[start:])
+	if last != "" {
+		items = append(items, last)
+	}
+
+	return items
+}
+
+// closingQuote returns the index of the quote closing the string that starts
+// at the beginning of value, or -1.
+func closingQuote(value string, quote byte) int {
+	for i := 1; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && quote == '"':
+			i++
+		case value[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// stripComment removes a trailing comment from an unquoted value.
+func stripComment(value string) string {
+	for _, marker := range []string{" #", " ;", "\t#", "\t;"} {
+		if i := strings.Index(value, marker); i >= 0 {
+			value = value[:i]
+		}
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// unquoteKey removes the quotes of a quoted key.
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+
+	return key
+}
@@ -0,0 +1,270 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+// writeConfig writes a configuration file in a temporary directory and
+// returns its path.
+func writeConfig(t *testing.T, name string, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "cli")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	path := filepath.Join(dir, name)
+	err = ioutil.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestCommand_AddConfigFlag_json(t *testing.T) {
+	path, cleanup := writeConfig(t, "config.json", `{
+		"verbose": true,
+		"port": 1,
+		"serve": {"port": 8080, "tags": ["a", "b"]}
+	}`)
+	defer cleanup()
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddConfigFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file"})
+	verbose := rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+	rootCommand.FlagName("-verbose").Persistent = true
+
+	subCommand1 := cli.NewCommand("serve", "serve Description")
+	port := subCommand1.Int("-p", "-port", 80, "Port")
+	tags := subCommand1.StringSlice("-t", "-tags", nil, "Tags")
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-config", path, "serve"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if !*verbose {
+		t.Fatalf("Expected %v but got %v", true, *verbose)
+	}
+	if *port != 8080 {
+		t.Fatalf("Expected %d but got %d", 8080, *port)
+	}
+	if !reflect.DeepEqual(*tags, []string{"a", "b"}) {
+		t.Fatalf("Expected %q but got %q", []string{"a", "b"}, *tags)
+	}
+}
+
+func TestCommand_AddConfigFlag_ini(t *testing.T) {
+	path, cleanup := writeConfig(t, "config.ini", `
+# Global options
+verbose = true
+
+[serve]
+port = 8080 ; inline comment
+tags = ["a", 'b,c']
+`)
+	defer cleanup()
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddConfigFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file"})
+	verbose := rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+	rootCommand.FlagName("-verbose").Persistent = true
+
+	subCommand1 := cli.NewCommand("serve", "serve Description")
+	port := subCommand1.Int("-p", "-port", 80, "Port")
+	tags := subCommand1.StringSlice("-t", "-tags", nil, "Tags")
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"serve", "-config=" + path})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if !*verbose {
+		t.Fatalf("Expected %v but got %v", true, *verbose)
+	}
+	if *port != 8080 {
+		t.Fatalf("Expected %d but got %d", 8080, *port)
+	}
+	if !reflect.DeepEqual(*tags, []string{"a", "b", "c"}) {
+		t.Fatalf("Expected %q but got %q", []string{"a", "b", "c"}, *tags)
+	}
+}
+
+func TestCommand_AddConfigFlag_precedence(t *testing.T) {
+	path, cleanup := writeConfig(t, "config.toml", `
+[serve]
+port = 8080
+tags = ["config"]
+`)
+	defer cleanup()
+
+	t.Setenv("CLI_TEST_TAGS", "env")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddConfigFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file"})
+
+	subCommand1 := cli.NewCommand("serve", "serve Description")
+	port := subCommand1.Int("-p", "-port", 80, "Port")
+	tags := subCommand1.StringSlice("-t", "-tags", nil, "Tags")
+	subCommand1.FlagName("-tags").EnvVars = []string{"CLI_TEST_TAGS"}
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"serve", "-config=" + path, "-port", "9090"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if *port != 9090 {
+		t.Fatalf("Expected %d but got %d", 9090, *port)
+	}
+	if !reflect.DeepEqual(*tags, []string{"env"}) {
+		t.Fatalf("Expected %q but got %q", []string{"env"}, *tags)
+	}
+}
+
+func TestCommand_AddConfigFlag_default(t *testing.T) {
+	path, cleanup := writeConfig(t, "config.json", `{"port": 8080}`)
+	defer cleanup()
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddConfigFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file", Default: path})
+	port := rootCommand.Int("-p", "-port", 80, "Port")
+
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if *port != 8080 {
+		t.Fatalf("Expected %d but got %d", 8080, *port)
+	}
+}
+
+func TestCommand_AddConfigFlag_missingFile(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddConfigFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file", Default: "/nonexistent/config.json"})
+	rootCommand.AddCommand(cli.NewCommand("serve", "serve Description"))
+
+	err := cli.ExecuteArgs(rootCommand, []string{"serve"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	err = cli.ExecuteArgs(rootCommand, []string{"serve", "-config", "/nonexistent/config.json"})
+	var configErr *cli.ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("Expected *cli.ConfigError but got %#v", err)
+	}
+}
+
+func TestCommand_AddConfigFlag_invalidValue(t *testing.T) {
+	path, cleanup := writeConfig(t, "config.json", `{"serve": {"port": "http"}}`)
+	defer cleanup()
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddConfigFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file"})
+
+	subCommand1 := cli.NewCommand("serve", "serve Description")
+	subCommand1.Int("-p", "-port", 80, "Port")
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"serve", "-config", path})
+	var flagErr *cli.InvalidFlagValueError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.InvalidFlagValueError but got %#v", err)
+	}
+}
+
+func TestRegisterConfigLoader(t *testing.T) {
+	// A loader for files with one "key: value" pair per line.
+	cli.RegisterConfigLoader(".kv", func(r io.Reader) (map[string]interface{}, error) {
+		config := make(map[string]interface{})
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			parts := strings.SplitN(scanner.Text(), ":", 2)
+			if len(parts) == 2 {
+				config[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		}
+		return config, scanner.Err()
+	})
+
+	path, cleanup := writeConfig(t, "config.kv", "verbose: true\n")
+	defer cleanup()
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddConfigFlag(&cli.Flag{ShortName: "-c", LongName: "-config", Description: "Config file"})
+	verbose := rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+	rootCommand.FlagName("-verbose").Persistent = true
+	rootCommand.AddCommand(cli.NewCommand("serve", "serve Description"))
+
+	err := cli.ExecuteArgs(rootCommand, []string{"serve", "-config", path})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if !*verbose {
+		t.Fatalf("Expected %v but got %v", true, *verbose)
+	}
+}
+
+func TestLoadINIConfig(t *testing.T) {
+	config, err := cli.LoadINIConfig(strings.NewReader(`
+name = "quoted \"value\"" # comment
+path = 'C:\literal'
+plain = some value
+[a.b]
+key = 1
+`))
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"name":  `quoted "value"`,
+		"path":  `C:\literal`,
+		"plain": "some value",
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{
+				"key": "1",
+			},
+		},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("Expected %v but got %v", expected, config)
+	}
+
+	_, err = cli.LoadINIConfig(strings.NewReader("invalid line\n"))
+	if err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+)
+
+// warnDeprecated emits a warning for each deprecated command in the path of
+// this command and for each deprecated flag set on it or on its parent
+// commands, from any source but the defaults.
+func (c *Command) warnDeprecated() {
+	for _, command := range c.path() {
+		if command.Deprecated != "" {
+			c.warn(fmt.Sprintf("command %q is deprecated, %s", command.CommandPath(), command.Deprecated))
+		}
+	}
+
+	for _, flag := range c.pathFlags() {
+		if flag.Deprecated != "" && flag.source != sourceDefault {
+			c.warn(fmt.Sprintf("flag %s is deprecated, %s", flagDisplayName(flag), flag.Deprecated))
+		}
+	}
+}
+
+// warn emits a warning, logged with the Logger of this command if
+// LogDeprecations is set on it or on any of its parents, or written to its
+// output otherwise.
+func (c *Command) warn(warning string) {
+	for command := c; command != nil; command = command.parent {
+		if command.LogDeprecations {
+			_ = c.Logger().Log("level", "warning", "msg", warning)
+			return
+		}
+	}
+
+	fmt.Fprintf(c.Output(), "warning: %s\n", warning)
+}
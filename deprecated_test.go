@@ -0,0 +1,122 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/cli"
+	"github.com/goombaio/log"
+)
+
+func TestCommand_hidden(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(buf)
+	rootCommand.AddPersistentFlag(&cli.Flag{LongName: "-debug", Description: "Debug output", Value: "false", Hidden: true})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-fmt", Description: "Output format", Deprecated: "use -format instead"})
+
+	debugCommand := cli.NewCommand("debug", "debug Description")
+	debugCommand.Hidden = true
+	rootCommand.AddCommand(debugCommand)
+
+	oldCommand := cli.NewCommand("old", "old Description")
+	oldCommand.Deprecated = "use 'programName new' instead"
+	rootCommand.AddCommand(oldCommand)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-help"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	for _, name := range []string{"debug", "old", "-fmt"} {
+		if strings.Contains(buf.String(), name) {
+			t.Fatalf("Expected no %q in %q", name, buf.String())
+		}
+	}
+
+	buf.Reset()
+	err = cli.ExecuteArgs(rootCommand, []string{"debug", "-debug"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if buf.String() != "" {
+		t.Fatalf("Expected no output but got %q", buf.String())
+	}
+}
+
+func TestCommand_deprecated(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-format=json"}, ""},
+		{[]string{"old"}, "warning: command \"programName old\" is deprecated, use 'programName new' instead\n"},
+		{[]string{"-fmt=json"}, "warning: flag -fmt is deprecated, use -format instead\n"},
+	}
+
+	buf := new(bytes.Buffer)
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(buf)
+	rootCommand.AddFlag(&cli.Flag{LongName: "-format", Description: "Output format"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-fmt", Description: "Output format", Deprecated: "use -format instead"})
+
+	oldCommand := cli.NewCommand("old", "old Description")
+	oldCommand.Deprecated = "use 'programName new' instead"
+	rootCommand.AddCommand(oldCommand)
+	rootCommand.AddCommand(cli.NewCommand("new", "new Description"))
+
+	for _, tc := range testCases {
+		buf.Reset()
+
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+
+		if buf.String() != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, buf.String(), tc.args)
+		}
+	}
+}
+
+func TestCommand_LogDeprecations(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(buf)
+	rootCommand.AddFlag(&cli.Flag{LongName: "-format", Description: "Output format"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-fmt", Description: "Output format", Deprecated: "use -format instead"})
+	rootCommand.LogDeprecations = true
+
+	logs := new(bytes.Buffer)
+	rootCommand.SetLogger(log.NewFmtLogger(logs))
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-fmt", "json"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if buf.String() != "" {
+		t.Fatalf("Expected no output but got %q", buf.String())
+	}
+	if !strings.Contains(logs.String(), "flag -fmt is deprecated, use -format instead") {
+		t.Fatalf("Expected the warning to be logged but got %q", logs.String())
+	}
+}
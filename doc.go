@@ -43,18 +43,18 @@ Example
 		}
 		rootCommand.SetOutput(os.Stdout)
 
-		os.Args = []string{programName}
+		rootCommand.SetArgs([]string{})
 
-		err := rootCommand.Execute()
+		err := cli.Execute(rootCommand)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
 		// Output:
-		// usage: programName [-help] <command> [args]
+		// usage: programName [-help] [args]
 		//
 		// Flags:
-		//   -h, -help	Show help
+		//   -h, -help  Show help message
 	}
 */
 package cli
@@ -0,0 +1,118 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Env returns the names of the environment variables bound to the flag, the
+// ones in EnvVars followed by the automatic one, if any. See
+// Command.EnvPrefix.
+func (f *Flag) Env() []string {
+	names := make([]string, 0, len(f.EnvVars)+1)
+	names = append(names, f.EnvVars...)
+
+	name := f.automaticEnv()
+	if name != "" {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// automaticEnv returns the name of the environment variable bound to the flag
+// by the EnvPrefix of its command or of a parent of it, or an empty string.
+//
+// The default help flag is not bound.
+func (f *Flag) automaticEnv() string {
+	if f.owner == nil || isHelpFlag(f) {
+		return ""
+	}
+
+	parts := []string{trimDashes(flagDisplayName(f))}
+
+	for command := f.owner; command != nil; command = command.parent {
+		if command.EnvPrefix != "" {
+			parts = append([]string{command.EnvPrefix}, parts...)
+
+			return envName(strings.Join(parts, "_"))
+		}
+		parts = append([]string{command.Name}, parts...)
+	}
+
+	return ""
+}
+
+// envName converts a name into an environment variable name, upper case and
+// with underscores instead of any character other than letters and digits.
+func envName(name string) string {
+	name = strings.ToUpper(name)
+
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// applyEnv sets the flags of this command, including the ones of its parent
+// commands, that were not given on the command line from their environment
+// variables.
+func (c *Command) applyEnv() error {
+	for _, flag := range c.pathFlags() {
+		if flag.source >= sourceEnv {
+			continue
+		}
+
+		for _, name := range flag.Env() {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+
+			err := flag.Set(value)
+			if err != nil {
+				return &InvalidFlagValueError{
+					Command: flag.owner,
+					Flag:    flagDisplayName(flag),
+					Value:   value,
+					Err:     fmt.Errorf("from environment variable %s: %w", name, err),
+				}
+			}
+			flag.source = sourceEnv
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// flagDisplayName returns the name a flag is shown with, its long name if it
+// has one or its short name otherwise.
+func flagDisplayName(flag *Flag) string {
+	if trimDashes(flag.LongName) != "" {
+		return flag.LongName
+	}
+
+	return flag.ShortName
+}
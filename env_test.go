@@ -0,0 +1,130 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestFlag_EnvVars(t *testing.T) {
+	t.Setenv("CLI_TEST_PORT", "8080")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	port := rootCommand.Int("-p", "-port", 80, "Port")
+	rootCommand.FlagName("-port").EnvVars = []string{"CLI_TEST_UNSET", "CLI_TEST_PORT"}
+
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if *port != 8080 {
+		t.Fatalf("Expected %d but got %d", 8080, *port)
+	}
+}
+
+func TestFlag_EnvVars_commandLinePrecedence(t *testing.T) {
+	t.Setenv("CLI_TEST_PORT", "8080")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	port := rootCommand.Int("-p", "-port", 80, "Port")
+	rootCommand.FlagName("-port").EnvVars = []string{"CLI_TEST_PORT"}
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-port=9090"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if *port != 9090 {
+		t.Fatalf("Expected %d but got %d", 9090, *port)
+	}
+}
+
+func TestFlag_EnvVars_invalidValue(t *testing.T) {
+	t.Setenv("CLI_TEST_PORT", "http")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Int("-p", "-port", 80, "Port")
+	rootCommand.FlagName("-port").EnvVars = []string{"CLI_TEST_PORT"}
+
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	var flagErr *cli.InvalidFlagValueError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.InvalidFlagValueError but got %#v", err)
+	}
+}
+
+func TestCommand_EnvPrefix(t *testing.T) {
+	t.Setenv("CLI_TEST_VERBOSE", "true")
+	t.Setenv("CLI_TEST_SUB_COMMAND1_LISTEN_ADDRESS", ":8080")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.EnvPrefix = "CLI_TEST"
+	verbose := rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+	rootCommand.FlagName("-verbose").Persistent = true
+
+	subCommand1 := cli.NewCommand("sub-command1", "subCommand1 Description")
+	address := subCommand1.String("-l", "-listen-address", ":80", "Listen address")
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"sub-command1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if !*verbose {
+		t.Fatalf("Expected %v but got %v", true, *verbose)
+	}
+	if *address != ":8080" {
+		t.Fatalf("Expected %q but got %q", ":8080", *address)
+	}
+
+	expected := []string{"CLI_TEST_SUB_COMMAND1_LISTEN_ADDRESS"}
+	if !reflect.DeepEqual(subCommand1.FlagName("-listen-address").Env(), expected) {
+		t.Fatalf("Expected %q but got %q", expected, subCommand1.FlagName("-listen-address").Env())
+	}
+
+	if len(subCommand1.FlagName("-help").Env()) != 0 {
+		t.Fatalf("Expected help flag not to be bound to the environment")
+	}
+}
+
+func TestCommand_Usage_env(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.EnvPrefix = "PROGRAM"
+	rootCommand.AddFlag(&cli.Flag{
+		ShortName:   "-p",
+		LongName:    "-port",
+		Description: "Port",
+		EnvVars:     []string{"PORT"},
+	})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+	rootCommand.Usage()
+
+	expected := "  -p, -port  Port [$PORT, $PROGRAM_PORT]\n"
+	if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+		t.Fatalf("Expected %q in %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// commandError is implemented by the errors that are related to a command,
+// like the errors found when parsing its commands and flags.
+type commandError interface {
+	error
+	command() *Command
+}
+
+// UnknownCommandError is returned when an argument does not match any of the
+// subcommands of a command.
+type UnknownCommandError struct {
+	// Command is the command whose subcommands were being matched.
+	Command *Command
+
+	// Name is the argument that did not match any subcommand.
+	Name string
+
+	// Suggestions are the names of the subcommands close to Name.
+	Suggestions []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	msg := fmt.Sprintf("unknown command %q for %q", e.Name, e.Command.CommandPath())
+
+	return msg + didYouMean(e.Suggestions)
+}
+
+func (e *UnknownCommandError) command() *Command {
+	return e.Command
+}
+
+// AmbiguousCommandError is returned when an argument is a prefix of more than
+// one of the subcommands of a command, see Command.PrefixMatching.
+type AmbiguousCommandError struct {
+	// Command is the command whose subcommands were being matched.
+	Command *Command
+
+	// Name is the argument that matched more than one subcommand.
+	Name string
+
+	// Candidates are the names of the subcommands matched by Name.
+	Candidates []string
+}
+
+func (e *AmbiguousCommandError) Error() string {
+	return fmt.Sprintf("ambiguous command %q for %q, could be: %s", e.Name, e.Command.CommandPath(), strings.Join(e.Candidates, ", "))
+}
+
+func (e *AmbiguousCommandError) command() *Command {
+	return e.Command
+}
+
+// UnknownFlagError is returned when a flag is not defined on a command.
+type UnknownFlagError struct {
+	// Command is the command whose flags were being parsed.
+	Command *Command
+
+	// Flag is the flag as it was given.
+	Flag string
+
+	// Suggestions are the names of the flags close to Flag.
+	Suggestions []string
+}
+
+func (e *UnknownFlagError) Error() string {
+	msg := fmt.Sprintf("unknown flag %s for %q", e.Flag, e.Command.CommandPath())
+
+	return msg + didYouMean(e.Suggestions)
+}
+
+func (e *UnknownFlagError) command() *Command {
+	return e.Command
+}
+
+// MissingFlagValueError is returned when a flag that requires a value is
+// given without one.
+type MissingFlagValueError struct {
+	// Command is the command whose flags were being parsed.
+	Command *Command
+
+	// Flag is the flag as it was given.
+	Flag string
+}
+
+func (e *MissingFlagValueError) Error() string {
+	return fmt.Sprintf("flag %s for %q needs an argument", e.Flag, e.Command.CommandPath())
+}
+
+func (e *MissingFlagValueError) command() *Command {
+	return e.Command
+}
+
+// InvalidFlagValueError is returned when the value of a flag can not be set.
+type InvalidFlagValueError struct {
+	// Command is the command whose flags were being parsed.
+	Command *Command
+
+	// Flag is the flag as it was given.
+	Flag string
+
+	// Value is the value that could not be set.
+	Value string
+
+	// Err is the error returned when setting the value.
+	Err error
+}
+
+func (e *InvalidFlagValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for flag %s of %q: %v", e.Value, e.Flag, e.Command.CommandPath(), e.Err)
+}
+
+// Unwrap returns the error returned when setting the value.
+func (e *InvalidFlagValueError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InvalidFlagValueError) command() *Command {
+	return e.Command
+}
+
+// didYouMean formats the suggestions to be appended to an error message.
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+
+	return "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+}
+
+// ArgumentsError is returned when the positional arguments of a command are
+// not valid.
+type ArgumentsError struct {
+	// Command is the command whose arguments were being validated.
+	Command *Command
+
+	// Err is the validation error.
+	Err error
+}
+
+func (e *ArgumentsError) Error() string {
+	return fmt.Sprintf("invalid arguments for %q: %v", e.Command.CommandPath(), e.Err)
+}
+
+// Unwrap returns the validation error.
+func (e *ArgumentsError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ArgumentsError) command() *Command {
+	return e.Command
+}
+
+// ConfigError is returned when a configuration file can not be loaded or
+// applied to the flags of a command.
+type ConfigError struct {
+	// Command is the command whose flags were being set.
+	Command *Command
+
+	// Path is the path of the configuration file.
+	Path string
+
+	// Err is the error found.
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("config file %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the error found.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ConfigError) command() *Command {
+	return e.Command
+}
+
+// RequiredFlagError is returned when a required flag is not supplied.
+type RequiredFlagError struct {
+	// Command is the command whose flags were being validated.
+	Command *Command
+
+	// Flag is the name of the flag.
+	Flag string
+}
+
+func (e *RequiredFlagError) Error() string {
+	return fmt.Sprintf("required flag %s of %q not set", e.Flag, e.Command.CommandPath())
+}
+
+func (e *RequiredFlagError) command() *Command {
+	return e.Command
+}
+
+// FlagValidationError is returned when the flags of a command are not valid.
+// It holds every violation found.
+type FlagValidationError struct {
+	// Command is the command whose flags were being validated.
+	Command *Command
+
+	// Errors are the violations found.
+	Errors []error
+}
+
+func (e *FlagValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("invalid flags for %q:\n\t%s", e.Command.CommandPath(), strings.Join(msgs, "\n\t"))
+}
+
+func (e *FlagValidationError) command() *Command {
+	return e.Command
+}
+
+// FlagGroupError is returned when a group of flags of a command violates its
+// constraint.
+type FlagGroupError struct {
+	// Command is the command whose flags were being validated.
+	Command *Command
+
+	// Kind is the constraint of the group.
+	Kind FlagGroupKind
+
+	// Flags are the names of the flags of the group.
+	Flags []string
+
+	// Set are the names of the flags of the group that were set.
+	Set []string
+}
+
+func (e *FlagGroupError) Error() string {
+	flags := strings.Join(e.Flags, ", ")
+
+	switch e.Kind {
+	case GroupMutuallyExclusive:
+		return fmt.Sprintf("flags %s are mutually exclusive but %s were set", flags, strings.Join(e.Set, ", "))
+	case GroupRequiredTogether:
+		return fmt.Sprintf("flags %s must be set together but only %s were set", flags, strings.Join(e.Set, ", "))
+	case GroupOneRequired:
+		return fmt.Sprintf("at least one of the flags %s is required", flags)
+	}
+
+	return fmt.Sprintf("flags %s are %s", flags, e.Kind)
+}
+
+func (e *FlagGroupError) command() *Command {
+	return e.Command
+}
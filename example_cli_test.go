@@ -26,8 +26,6 @@ import (
 )
 
 func ExampleCommand() {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Short Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -37,6 +35,8 @@ func ExampleCommand() {
 	}
 	rootCommand.SetLogger(log.NewFmtLogger(os.Stderr))
 
+	rootCommand.SetArgs([]string{})
+
 	err := cli.Execute(rootCommand)
 	if err != nil {
 		_ = rootCommand.Logger().Log("ERROR:", err)
@@ -47,8 +47,6 @@ func ExampleCommand() {
 }
 
 func ExampleCommand_Usage() {
-	os.Args = []string{"programName", "-help"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Short Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -58,25 +56,23 @@ func ExampleCommand_Usage() {
 	}
 	rootCommand.SetLogger(log.NewFmtLogger(os.Stderr))
 
+	rootCommand.SetArgs([]string{"-help"})
+
 	err := cli.Execute(rootCommand)
 	if err != nil {
 		_ = rootCommand.Logger().Log("ERROR:", err)
 		os.Exit(1)
 	}
 	// Output:
-	// usage: programName [-help] <command> [args]
+	// usage: programName [-help] [args]
 	//
 	//   rootCommand Long Description
 	//
 	// Flags:
-	//   -h, -help	Show help message
-	//
-	// Use programName [command] -help for more information about a command.
+	//   -h, -help  Show help message
 }
 
 func ExampleCommand_subCommand() {
-	os.Args = []string{"programName", "subCommand1"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -95,6 +91,8 @@ func ExampleCommand_subCommand() {
 	}
 	rootCommand.AddCommand(subCommand1)
 
+	rootCommand.SetArgs([]string{"subCommand1"})
+
 	err := cli.Execute(rootCommand)
 	if err != nil {
 		_ = rootCommand.Logger().Log("ERROR:", err)
@@ -105,8 +103,6 @@ func ExampleCommand_subCommand() {
 }
 
 func ExampleCommand_subCommand_usage() {
-	os.Args = []string{"programName", "subCommand1", "-help"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -125,18 +121,18 @@ func ExampleCommand_subCommand_usage() {
 	}
 	rootCommand.AddCommand(subCommand1)
 
+	rootCommand.SetArgs([]string{"subCommand1", "-help"})
+
 	err := cli.Execute(rootCommand)
 	if err != nil {
 		_ = rootCommand.Logger().Log("ERROR:", err)
 		os.Exit(1)
 	}
 	// Output:
-	// usage: subCommand1 [-help] <command> [args]
+	// usage: programName subCommand1 [-help] [args]
 	//
 	//   subCommand1 Long Description
 	//
 	// Flags:
-	//   -h, -help	Show help message
-	//
-	// Use subCommand1 [command] -help for more information about a command.
+	//   -h, -help  Show help message
 }
@@ -17,15 +17,82 @@
 
 package cli
 
-// Flag implements a command line flag
+import (
+	"strings"
+)
+
+// Flag implements a command line flag.
+//
+// Value holds the string representation of the flag value. When the flag is
+// backed by a typed Var, Value is kept in sync with it.
+//
+// A flag is a boolean flag, that does not need a value on the command line,
+// when its Var is a boolean value or, for flags without a Var, when its Value
+// is "true" or "false".
 type Flag struct {
 	ShortName   string
 	LongName    string
 	Description string
 	Value       string
 	Parsed      bool
+
+	// Var is the typed value of the flag, if any.
+	Var Value
+
+	// Persistent makes the flag available to every sub-command of the
+	// command it is added to.
+	Persistent bool
+
+	// Default is the value the flag is set to when it is not supplied on the
+	// command line, from the environment or from a configuration file.
+	Default string
+
+	// Required makes the flag mandatory, unless it has a default value, given
+	// by Default or by the value the flag is added with.
+	Required bool
+
+	// Validators validate the value of the flag when it is supplied.
+	Validators []FlagValidator
+
+	// EnvVars are the names of the environment variables that supply the
+	// flag value when it is not given on the command line. The first one
+	// that is set is used.
+	EnvVars []string
+
+	// Complete returns the completion candidates for the flag value.
+	Complete CompletionFunc
+
+	// Hidden hides the flag from the usage output, the suggestions and the
+	// completions. It is still parsed as any other flag.
+	Hidden bool
+
+	// Deprecated marks the flag as deprecated with a message that usually
+	// names its replacement, e.g. "use -new instead". A deprecated flag still
+	// works, but a warning with the message is emitted when it is set, and it
+	// is hidden like a Hidden flag.
+	Deprecated string
+
+	// owner is the command the flag was added to.
+	owner *Command
+
+	// source is where the current value of the flag comes from.
+	source flagSource
+
+	// initial is the value of the flag when it was added to its command,
+	// which it is reset to before every execution.
+	initial string
 }
 
+// flagSource is where the value of a flag comes from.
+type flagSource int
+
+const (
+	sourceDefault flagSource = iota
+	sourceConfig
+	sourceEnv
+	sourceCommandLine
+)
+
 // IsFlag checks if an string is a flag or not.
 //
 // It will be a flag if it has the format:
@@ -36,3 +103,99 @@ type Flag struct {
 func IsFlag(str string) bool {
 	return ((len(str) >= 3 && str[1] == '-') || (len(str) >= 2 && str[0] == '-' && str[1] != '-'))
 }
+
+// Set sets the value of the flag from its string representation.
+func (f *Flag) Set(value string) error {
+	if f.Var == nil {
+		f.Value = value
+		return nil
+	}
+
+	err := f.Var.Set(value)
+	if err != nil {
+		return err
+	}
+	f.Value = f.Var.String()
+
+	return nil
+}
+
+// reset resets the flag to its state before being parsed, with its initial
+// value.
+func (f *Flag) reset() {
+	f.Parsed = false
+	f.source = sourceDefault
+
+	if f.Var == nil {
+		f.Value = f.initial
+		return
+	}
+
+	if r, ok := f.Var.(resetter); ok {
+		r.reset()
+	} else {
+		_ = f.Var.Set(f.initial)
+	}
+	f.Value = f.Var.String()
+}
+
+// matches checks if name, with or without leading dashes, is the short or the
+// long name of this flag.
+func (f *Flag) matches(name string) bool {
+	name = trimDashes(name)
+	if name == "" {
+		return false
+	}
+
+	return name == trimDashes(f.ShortName) || name == trimDashes(f.LongName)
+}
+
+// isHelpFlag checks if the flag is the default help flag.
+func isHelpFlag(flag *Flag) bool {
+	return flag.ShortName == "-h" || flag.LongName == "-help"
+}
+
+// shadowed checks if a flag with any of the names of flag is already on the
+// list of flags.
+func shadowed(flags []*Flag, flag *Flag) bool {
+	for _, f := range flags {
+		if f.matches(flag.ShortName) || f.matches(flag.LongName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// visible checks if the flag is shown in the usage output, the suggestions
+// and the completions.
+func (f *Flag) visible() bool {
+	return !f.Hidden && f.Deprecated == ""
+}
+
+// isBoolFlag checks if the flag is a boolean flag.
+func (f *Flag) isBoolFlag() bool {
+	if f.Var != nil {
+		bv, ok := f.Var.(boolFlag)
+		return ok && bv.IsBoolFlag()
+	}
+
+	return f.Value == "true" || f.Value == "false"
+}
+
+// splitFlag splits a flag argument in its name and its `=` separated value.
+func splitFlag(arg string) (name string, value string, hasValue bool) {
+	name = arg
+	if i := strings.Index(arg, "="); i >= 0 {
+		name = arg[:i]
+		value = arg[i+1:]
+		hasValue = true
+	}
+
+	return name, value, hasValue
+}
+
+// trimDashes removes the leading dashes of a flag name.
+func trimDashes(name string) string {
+	return strings.TrimLeft(name, "-")
+}
@@ -0,0 +1,189 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"strings"
+)
+
+// flagsTerminator is the argument that terminates the flags in the GNU flag
+// syntax, the arguments that follow it are positional arguments.
+const flagsTerminator = "--"
+
+// gnuFlags checks if the GNU flag syntax is enabled on this command or on any
+// of its parents. See Command.GNUFlags.
+func (c *Command) gnuFlags() bool {
+	for command := c; command != nil; command = command.parent {
+		if command.GNUFlags {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseGNUFlag parses the flag, or the short flags, at the start of the
+// arguments using the GNU flag syntax, and returns the number of arguments
+// it consumed.
+//
+// Flags are accepted in the forms:
+// * --flag=value
+// * --flag value
+// * --flag (only for boolean flags)
+// * -f value
+// * -fvalue
+// * -xvf (several boolean flags, the last one can take a value)
+func (c *Command) parseGNUFlag(args []string) (int, error) {
+	arg := args[0]
+
+	// A long flag
+	if strings.HasPrefix(arg, "--") {
+		name, value, hasValue := splitFlag(arg)
+
+		flag := c.longFlag(name)
+		if flag == nil {
+			return 1, &UnknownFlagError{
+				Command:     c,
+				Flag:        name,
+				Suggestions: c.gnuFlagSuggestions(name),
+			}
+		}
+
+		consumed := 1
+		switch {
+		case hasValue:
+		case flag.isBoolFlag():
+			value = "true"
+		case len(args) > 1:
+			consumed = 2
+			value = args[1]
+		default:
+			return consumed, &MissingFlagValueError{Command: c, Flag: name}
+		}
+
+		return consumed, c.setFlag(flag, name, value)
+	}
+
+	// One or more short flags
+	shorts := []rune(arg[1:])
+	for j, short := range shorts {
+		name := "-" + string(short)
+
+		flag := c.shortFlag(short)
+		if flag == nil {
+			return 1, &UnknownFlagError{
+				Command:     c,
+				Flag:        name,
+				Suggestions: c.gnuFlagSuggestions(name),
+			}
+		}
+
+		rest := string(shorts[j+1:])
+
+		// A boolean flag followed by more short flags
+		if flag.isBoolFlag() && !strings.HasPrefix(rest, "=") {
+			err := c.setFlag(flag, name, "true")
+			if err != nil {
+				return 1, err
+			}
+			continue
+		}
+
+		// A flag with its value attached, or in the next argument
+		switch {
+		case rest != "":
+			return 1, c.setFlag(flag, name, strings.TrimPrefix(rest, "="))
+		case len(args) > 1:
+			return 2, c.setFlag(flag, name, args[1])
+		}
+
+		return 1, &MissingFlagValueError{Command: c, Flag: name}
+	}
+
+	return 1, nil
+}
+
+// gnuFlagTakesValue checks if arg is a flag of this command, in the GNU flag
+// syntax, that will consume the next argument as its value.
+func (c *Command) gnuFlagTakesValue(arg string) bool {
+	if strings.HasPrefix(arg, "--") {
+		name, _, hasValue := splitFlag(arg)
+		flag := c.longFlag(name)
+
+		return !hasValue && flag != nil && !flag.isBoolFlag()
+	}
+
+	shorts := []rune(arg[1:])
+	for j, short := range shorts {
+		flag := c.shortFlag(short)
+		if flag == nil {
+			return false
+		}
+		if !flag.isBoolFlag() {
+			return j == len(shorts)-1
+		}
+	}
+
+	return false
+}
+
+// longFlag returns the flag of this command, including the inherited ones,
+// whose long name is name, given with its leading dashes.
+func (c *Command) longFlag(name string) *Flag {
+	for _, flag := range c.availableFlags() {
+		if flag.LongName != "" && trimDashes(flag.LongName) == trimDashes(name) {
+			return flag
+		}
+	}
+
+	return nil
+}
+
+// shortFlag returns the flag of this command, including the inherited ones,
+// whose short name is the letter short.
+func (c *Command) shortFlag(short rune) *Flag {
+	for _, flag := range c.availableFlags() {
+		if trimDashes(flag.ShortName) == string(short) {
+			return flag
+		}
+	}
+
+	return nil
+}
+
+// gnuFlagName returns a flag name as written in the GNU flag syntax, one dash
+// for short names and two dashes for long names.
+func gnuFlagName(name string, long bool) string {
+	if long {
+		return "--" + trimDashes(name)
+	}
+
+	return "-" + trimDashes(name)
+}
+
+// gnuFlagSuggestions returns the names of the flags of this command, including
+// the inherited ones, that are close to name, as written in the GNU flag
+// syntax.
+func (c *Command) gnuFlagSuggestions(name string) []string {
+	suggestions := c.flagSuggestions(name)
+	for i, suggestion := range suggestions {
+		suggestions[i] = gnuFlagName(suggestion, len(trimDashes(suggestion)) > 1)
+	}
+
+	return suggestions
+}
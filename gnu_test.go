@@ -0,0 +1,148 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_GNUFlags(t *testing.T) {
+	testCases := []struct {
+		args        []string
+		extract     bool
+		verbose     bool
+		file        string
+		positionals []string
+	}{
+		{[]string{"-xvf", "archive.tar"}, true, true, "archive.tar", []string{}},
+		{[]string{"-xfarchive.tar", "a"}, true, false, "archive.tar", []string{"a"}},
+		{[]string{"-f=archive.tar"}, false, false, "archive.tar", []string{}},
+		{[]string{"--extract", "--file", "archive.tar", "a"}, true, false, "archive.tar", []string{"a"}},
+		{[]string{"--verbose=false", "--file=archive.tar"}, false, false, "archive.tar", []string{}},
+		{[]string{"-v", "--", "-x", "--file"}, false, true, "", []string{"-x", "--file"}},
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.GNUFlags = true
+	rootCommand.AddArg(&cli.Arg{Name: "files", Variadic: true})
+
+	extract := rootCommand.Bool("-x", "-extract", false, "Extract files")
+	verbose := rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+	file := rootCommand.String("-f", "-file", "", "Archive file")
+
+	for _, tc := range testCases {
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+
+		if *extract != tc.extract || *verbose != tc.verbose || *file != tc.file {
+			t.Fatalf("Expected %v, %v, %q but got %v, %v, %q for %q", tc.extract, tc.verbose, tc.file, *extract, *verbose, *file, tc.args)
+		}
+		if !reflect.DeepEqual(rootCommand.Arguments(), tc.positionals) {
+			t.Fatalf("Expected %q but got %q for %q", tc.positionals, rootCommand.Arguments(), tc.args)
+		}
+	}
+}
+
+func TestCommand_GNUFlags_errors(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-extract"}, "unknown flag -e for \"programName\""},
+		{[]string{"--extrac"}, "unknown flag --extrac for \"programName\"\n\nDid you mean this?\n\t--extract"},
+		{[]string{"-xf"}, "flag -f for \"programName\" needs an argument"},
+		{[]string{"--file"}, "flag --file for \"programName\" needs an argument"},
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.GNUFlags = true
+	rootCommand.SetOutput(new(bytes.Buffer))
+	rootCommand.Bool("-x", "-extract", false, "Extract files")
+	rootCommand.String("-f", "-file", "", "Archive file")
+
+	for _, tc := range testCases {
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if err == nil {
+			t.Fatalf("Expected error but got nil for %q", tc.args)
+		}
+		if err.Error() != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, err.Error(), tc.args)
+		}
+	}
+}
+
+func TestCommand_GNUFlags_subCommands(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.GNUFlags = true
+	output := rootCommand.String("-o", "-output", "", "Output file")
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.ValidateArgs = cli.MinimumArgs(0)
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1", "-o", "subCommand1", "--", "subCommand1"})
+	var flagErr *cli.UnknownFlagError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.UnknownFlagError but got %#v", err)
+	}
+
+	rootCommand.Flags()[len(rootCommand.Flags())-1].Persistent = true
+	err = cli.ExecuteArgs(rootCommand, []string{"subCommand1", "-osubCommand1", "--", "subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if *output != "subCommand1" {
+		t.Fatalf("Expected %q but got %q", "subCommand1", *output)
+	}
+	if !reflect.DeepEqual(subCommand1.Arguments(), []string{"subCommand1"}) {
+		t.Fatalf("Expected %q but got %q", []string{"subCommand1"}, subCommand1.Arguments())
+	}
+}
+
+func TestCommand_GNUFlags_usage(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.GNUFlags = true
+	rootCommand.AddArg(&cli.Arg{Name: "files", Variadic: true})
+	rootCommand.Bool("-x", "-extract", false, "Extract files")
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"--help"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	for _, expected := range []string{
+		"usage: programName [--help] [files...]\n",
+		"  -h, --help     Show help message\n",
+		"  -x, --extract  Extract files\n",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("Expected %q in %q", expected, buf.String())
+		}
+	}
+}
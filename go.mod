@@ -1,3 +1,5 @@
 module github.com/goombaio/cli
 
+go 1.17
+
 require github.com/goombaio/log v0.0.0-20181006234330-b2d335e3400f
@@ -0,0 +1,130 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+// FlagGroupKind is the kind of constraint a group of flags is subject to.
+type FlagGroupKind int
+
+const (
+	// GroupMutuallyExclusive groups flags of which at most one can be set.
+	GroupMutuallyExclusive FlagGroupKind = iota
+
+	// GroupRequiredTogether groups flags that must be set all or none.
+	GroupRequiredTogether
+
+	// GroupOneRequired groups flags of which at least one must be set.
+	GroupOneRequired
+)
+
+// String returns the description of the constraint.
+func (k FlagGroupKind) String() string {
+	switch k {
+	case GroupMutuallyExclusive:
+		return "mutually exclusive"
+	case GroupRequiredTogether:
+		return "required together"
+	case GroupOneRequired:
+		return "one required"
+	}
+
+	return "unknown"
+}
+
+// flagGroup is a group of flags subject to a constraint.
+type flagGroup struct {
+	kind  FlagGroupKind
+	names []string
+}
+
+// MarkFlagsMutuallyExclusive makes the flags with the given names mutually
+// exclusive, so an error is returned if more than one of them is set.
+func (c *Command) MarkFlagsMutuallyExclusive(names ...string) {
+	c.addFlagGroup(GroupMutuallyExclusive, names)
+}
+
+// MarkFlagsRequiredTogether makes the flags with the given names required
+// together, so an error is returned if some but not all of them are set.
+func (c *Command) MarkFlagsRequiredTogether(names ...string) {
+	c.addFlagGroup(GroupRequiredTogether, names)
+}
+
+// MarkFlagsOneRequired makes at least one of the flags with the given names
+// required, so an error is returned if none of them is set.
+func (c *Command) MarkFlagsOneRequired(names ...string) {
+	c.addFlagGroup(GroupOneRequired, names)
+}
+
+// addFlagGroup adds a group of flags to this command.
+func (c *Command) addFlagGroup(kind FlagGroupKind, names []string) {
+	c.flagGroups = append(c.flagGroups, &flagGroup{kind: kind, names: names})
+}
+
+// validateFlagGroups checks the flag groups of this command and of its parent
+// commands, and returns an error for each violated group.
+//
+// A flag is set when it is supplied from any source other than its default.
+// The names are resolved on the command declaring the group, whose flags can
+// be given in its part of the command line, and a name that is not a flag of
+// it is reported as an unknown flag.
+func (c *Command) validateFlagGroups() []error {
+	errs := make([]error, 0)
+
+	for _, command := range c.path() {
+		for _, group := range command.flagGroups {
+			flags := make([]string, 0, len(group.names))
+			set := make([]string, 0, len(group.names))
+			known := true
+			for _, name := range group.names {
+				flag := command.FlagName(name)
+				if flag == nil {
+					errs = append(errs, &UnknownFlagError{Command: command, Flag: name})
+					known = false
+					break
+				}
+				flags = append(flags, flagDisplayName(flag))
+				if flag.source != sourceDefault {
+					set = append(set, flagDisplayName(flag))
+				}
+			}
+			if !known {
+				continue
+			}
+
+			violated := false
+			switch group.kind {
+			case GroupMutuallyExclusive:
+				violated = len(set) > 1
+			case GroupRequiredTogether:
+				violated = len(set) > 0 && len(set) < len(flags)
+			case GroupOneRequired:
+				violated = len(set) == 0
+			}
+
+			if violated {
+				errs = append(errs, &FlagGroupError{
+					Command: c,
+					Kind:    group.kind,
+					Flags:   flags,
+					Set:     set,
+				})
+			}
+		}
+	}
+
+	return errs
+}
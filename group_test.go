@@ -0,0 +1,134 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_flagGroups(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected []cli.FlagGroupKind
+	}{
+		{[]string{"-file=f"}, nil},
+		{[]string{"-url=u", "-user=a", "-password=b"}, nil},
+		{[]string{"-file=f", "-url=u"}, []cli.FlagGroupKind{cli.GroupMutuallyExclusive}},
+		{[]string{}, []cli.FlagGroupKind{cli.GroupOneRequired}},
+		{[]string{"-file=f", "-user=a"}, []cli.FlagGroupKind{cli.GroupRequiredTogether}},
+		{[]string{"-password=b"}, []cli.FlagGroupKind{cli.GroupOneRequired, cli.GroupRequiredTogether}},
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{LongName: "-file", Description: "Input file"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-url", Description: "Input URL"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-user", Description: "User"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-password", Description: "Password"})
+	rootCommand.MarkFlagsMutuallyExclusive("-file", "-url")
+	rootCommand.MarkFlagsOneRequired("-file", "-url")
+	rootCommand.MarkFlagsRequiredTogether("-user", "-password")
+
+	for _, tc := range testCases {
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if len(tc.expected) == 0 {
+			if err != nil {
+				t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+			}
+			continue
+		}
+
+		var validationErr *cli.FlagValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected *cli.FlagValidationError but got %#v for %q", err, tc.args)
+		}
+		if len(validationErr.Errors) != len(tc.expected) {
+			t.Fatalf("Expected %d violations but got %d for %q", len(tc.expected), len(validationErr.Errors), tc.args)
+		}
+		for i, kind := range tc.expected {
+			var groupErr *cli.FlagGroupError
+			if !errors.As(validationErr.Errors[i], &groupErr) || groupErr.Kind != kind {
+				t.Fatalf("Expected %s violation but got %s for %q", kind, validationErr.Errors[i], tc.args)
+			}
+		}
+	}
+}
+
+func TestCommand_flagGroups_unknownFlag(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.String("", "-file", "", "Input file")
+	rootCommand.String("", "-url", "", "Input URL")
+	rootCommand.MarkFlagsMutuallyExclusive("-file", "-urll")
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-file=f", "-url=u"})
+
+	var validationErr *cli.FlagValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+		t.Fatalf("Expected *cli.FlagValidationError but got %#v", err)
+	}
+	var flagErr *cli.UnknownFlagError
+	if !errors.As(validationErr.Errors[0], &flagErr) || flagErr.Flag != "-urll" {
+		t.Fatalf("Expected *cli.UnknownFlagError for %q but got %s", "-urll", validationErr.Errors[0])
+	}
+}
+
+func TestFlagGroupError_Error(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{LongName: "-file", Description: "Input file"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-url", Description: "Input URL"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-user", Description: "User"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-password", Description: "Password"})
+	rootCommand.MarkFlagsMutuallyExclusive("-file", "-url")
+	rootCommand.MarkFlagsOneRequired("-file", "-url")
+	rootCommand.MarkFlagsRequiredTogether("-user", "-password")
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-file=f", "-url=u", "-user=a"})
+
+	expected := "invalid flags for \"programName\":\n"
+	expected += "\tflags -file, -url are mutually exclusive but -file, -url were set\n"
+	expected += "\tflags -user, -password must be set together but only -user were set"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %q but got %v", expected, err)
+	}
+}
+
+func TestCommand_Usage_flagGroups(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{LongName: "-file", Description: "Input file"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-url", Description: "Input URL"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-user", Description: "User"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-password", Description: "Password"})
+	rootCommand.MarkFlagsMutuallyExclusive("-file", "-url")
+	rootCommand.MarkFlagsOneRequired("-file", "-url")
+	rootCommand.MarkFlagsRequiredTogether("-user", "-password")
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+	rootCommand.Usage()
+
+	expected := "Flag Groups:\n"
+	expected += "  -file, -url       mutually exclusive\n"
+	expected += "  -file, -url       one required\n"
+	expected += "  -user, -password  required together\n"
+	if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+		t.Fatalf("Expected %q in %q", expected, buf.String())
+	}
+}
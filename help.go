@@ -0,0 +1,153 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// helpCommandName is the name of the help command added to the root command.
+const helpCommandName = "help"
+
+// helpTopicsName is the argument of the help command that lists the help
+// topics.
+const helpTopicsName = "topics"
+
+// AddHelpTopic adds a help topic to this command.
+//
+// A help topic is a help-only command, a guide about a subject instead of an
+// action, whose LongDescription is shown when it is invoked, either as
+// 'programName <topic>' or 'programName help <topic>'. Help topics are listed
+// apart from the commands in the usage output and by 'programName help
+// topics'.
+func (c *Command) AddHelpTopic(topic *Command) {
+	topic.helpTopic = true
+	topic.Run = func(cmd *Command) error {
+		cmd.helpTopicUsage()
+		return nil
+	}
+
+	c.AddCommand(topic)
+}
+
+// helpTopicUsage puts out the text of this help topic.
+func (c *Command) helpTopicUsage() {
+	text := c.LongDescription
+	if text == "" {
+		text = c.ShortDescription
+	}
+
+	fmt.Fprintln(c.Output(), strings.TrimRight(text, "\n"))
+}
+
+// setupHelpCommand adds the command 'help [command...]' to this command, if
+// it has sub-commands and none of them is already named help.
+func (c *Command) setupHelpCommand() {
+	if len(c.commands) == 0 || c.commandName(helpCommandName) != nil {
+		return
+	}
+
+	helpCommand := NewCommand(helpCommandName, "Help about any command")
+	helpCommand.LongDescription = "Help shows the usage of any command, or the text of a help topic.\n" +
+		"Use '" + c.CommandPath() + " " + helpCommandName + " " + helpTopicsName + "' to list the help topics."
+	helpCommand.AddArg(&Arg{
+		Name:        "command",
+		Description: "Path of the command or the help topic",
+		Variadic:    true,
+		Complete: func(cmd *Command, prefix string) []Completion {
+			target, err := c.ParseCommands(cmd.Arguments())
+			if err != nil {
+				return nil
+			}
+
+			completions := make([]Completion, 0)
+			for _, command := range target.commands {
+				if command.visible() && strings.HasPrefix(command.Name, prefix) {
+					completions = append(completions, Completion{command.Name, command.ShortDescription})
+				}
+			}
+			return completions
+		},
+	})
+	helpCommand.Run = func(cmd *Command) error {
+		args := cmd.Arguments()
+
+		if len(args) == 1 && args[0] == helpTopicsName && c.commandName(helpTopicsName) == nil {
+			c.helpTopicsUsage()
+			return nil
+		}
+
+		target, err := c.ParseCommands(args)
+		if err != nil {
+			return err
+		}
+
+		if target.helpTopic {
+			target.helpTopicUsage()
+			return nil
+		}
+
+		return target.usage()
+	}
+
+	c.AddCommand(helpCommand)
+}
+
+// helpTopicsUsage puts out the list of the help topics of this command and of
+// its sub-commands.
+func (c *Command) helpTopicsUsage() {
+	topics := c.helpTopics()
+	if len(topics) == 0 {
+		fmt.Fprintln(c.Output(), "No help topics.")
+		return
+	}
+
+	root := c.CommandPath() + " "
+
+	padding := 0
+	for _, topic := range topics {
+		if n := len(strings.TrimPrefix(topic.CommandPath(), root)); n > padding {
+			padding = n
+		}
+	}
+
+	fmt.Fprintln(c.Output(), "Help Topics:")
+	for _, topic := range topics {
+		name := strings.TrimPrefix(topic.CommandPath(), root)
+		fmt.Fprintf(c.Output(), "  %s  %s\n", rpad(name, padding), topic.ShortDescription)
+	}
+}
+
+// helpTopics returns the help topics of this command and of its
+// sub-commands.
+func (c *Command) helpTopics() []*Command {
+	topics := make([]*Command, 0)
+	for _, command := range c.commands {
+		if !command.visible() {
+			continue
+		}
+		if command.helpTopic {
+			topics = append(topics, command)
+			continue
+		}
+		topics = append(topics, command.helpTopics()...)
+	}
+
+	return topics
+}
@@ -0,0 +1,133 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_help(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			[]string{"help", "subCommand1", "subCommand2"},
+			"usage: programName subCommand1 subCommand2 [-help] [args]\n\nFlags:\n  -h, -help  Show help message\n",
+		},
+		{
+			[]string{"help", "environment"},
+			"The environment variables are...\n",
+		},
+		{
+			[]string{"environment"},
+			"The environment variables are...\n",
+		},
+		{
+			[]string{"help", "subCommand1", "formats"},
+			"Output formats\n",
+		},
+		{
+			[]string{"help", "topics"},
+			"Help Topics:\n  subCommand1 formats  Output formats\n  environment          Environment variables\n",
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(buf)
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	rootCommand.AddCommand(subCommand1)
+	subCommand1.AddCommand(cli.NewCommand("subCommand2", "subCommand2 Description"))
+
+	environment := cli.NewCommand("environment", "Environment variables")
+	environment.LongDescription = "The environment variables are..."
+	rootCommand.AddHelpTopic(environment)
+
+	subCommand1.AddHelpTopic(cli.NewCommand("formats", "Output formats"))
+
+	for _, tc := range testCases {
+		buf.Reset()
+
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+
+		if buf.String() != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, buf.String(), tc.args)
+		}
+	}
+}
+
+func TestCommand_help_unknownCommand(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(new(bytes.Buffer))
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	rootCommand.AddCommand(subCommand1)
+	subCommand1.AddHelpTopic(cli.NewCommand("formats", "Output formats"))
+
+	err := cli.ExecuteArgs(rootCommand, []string{"help", "subCommand1", "unknown"})
+
+	var unknownErr *cli.UnknownCommandError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("Expected *cli.UnknownCommandError but got %#v", err)
+	}
+	if unknownErr.Command.CommandPath() != "programName subCommand1" {
+		t.Fatalf("Expected %q but got %q", "programName subCommand1", unknownErr.Command.CommandPath())
+	}
+}
+
+func TestCommand_Usage_helpTopics(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(buf)
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	rootCommand.AddCommand(subCommand1)
+	subCommand1.AddHelpTopic(cli.NewCommand("formats", "Output formats"))
+	rootCommand.AddHelpTopic(cli.NewCommand("environment", "Environment variables"))
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-help"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	expected := "usage: programName [-help] <command>\n"
+	expected += "\n"
+	expected += "Commands:\n"
+	expected += "  subCommand1  subCommand1 Description\n"
+	expected += "  help         Help about any command\n"
+	expected += "\n"
+	expected += "Help Topics:\n"
+	expected += "  environment  Environment variables\n"
+	expected += "\n"
+	expected += "Flags:\n"
+	expected += "  -h, -help    Show help message\n"
+	expected += "\n"
+	expected += "Use programName [command] -help for more information about a command.\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected %q but got %q", expected, buf.String())
+	}
+}
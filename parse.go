@@ -17,57 +17,223 @@
 
 package cli
 
-// ParseCommands ...
-func (c *Command) ParseCommands(args []string) *Command {
+import (
+	"strings"
+)
+
+// ParseMode is how the flags of a command are parsed among its positional
+// arguments.
+type ParseMode int
+
+const (
+	// ParseInterspersed parses the flags anywhere in the arguments of the
+	// command, before and after its positional arguments.
+	ParseInterspersed ParseMode = iota
+
+	// ParseStopAtFirstArg stops parsing flags at the first positional
+	// argument of the command. It and all the arguments that follow it are
+	// positional arguments, even if they look like flags, which is useful for
+	// commands that pass them through to another program.
+	ParseStopAtFirstArg
+)
+
+// ParseCommands walks the arguments routing through the command tree and
+// returns the command that will be selected for execution.
+//
+// The arguments are split in segments, one for each command in the route,
+// that start at the name of the command. The flags of a segment belong to its
+// command and they are skipped along the way, as well as the values of the
+// flags that do not use the `=` separated form, so they are not mistaken for
+// a subcommand name. The rest of the arguments that follow the selected
+// command are its positional arguments.
+//
+// Subcommands are matched by their name or by one of their aliases, or by a
+// prefix of them if PrefixMatching is enabled.
+//
+// An *UnknownCommandError is returned if an argument does not match any of
+// the subcommands of a command that has subcommands and does not accept
+// positional arguments, and an *AmbiguousCommandError if it is a prefix of
+// more than one of them.
+func (c *Command) ParseCommands(args []string) (*Command, error) {
+	return c.parse(args, false)
+}
+
+// ParseFlags parses the flags from the arguments that follow this command on
+// the command line, and returns the command selected for execution, as
+// ParseCommands does.
+//
+// Each flag is parsed by the command of the segment it is given in, so it
+// has to be defined on it or be a persistent flag inherited by it. See
+// ParseCommands.
+//
+// Flags are accepted in the forms:
+// * -flag=value
+// * -flag value
+// * -flag (only for boolean flags)
+//
+// Both one and two leading dashes are accepted for any flag, unless GNUFlags
+// is enabled. A flag value given as a separate argument is consumed, so it is
+// not taken as a subcommand or as a positional argument.
+//
+// An *UnknownFlagError is returned if a flag is not defined on the command of
+// its segment, a *MissingFlagValueError if a flag that requires a value has
+// none, and an *InvalidFlagValueError if a flag value can not be set.
+func (c *Command) ParseFlags(args []string) (*Command, error) {
+	return c.parse(args, true)
+}
+
+// parse walks the arguments routing through the command tree, parsing the
+// flags if parseFlags is set, and returns the command selected for execution.
+func (c *Command) parse(args []string, parseFlags bool) (*Command, error) {
 	cmd := c
+	positionals := make([]string, 0)
+	flagsEnded := false
 
-	// Do not parse if there is no subcommands
-	if len(cmd.commands) == 0 {
-		return cmd
-	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 
-	for _, arg := range args {
-		offsetArgs := 1
-		candidate := ""
+		if flagsEnded {
+			positionals = append(positionals, arg)
+			continue
+		}
 
-		if !IsFlag(arg) {
-			candidate = arg
-		} else {
-			offsetArgs++
+		// The rest of the arguments are positional arguments
+		if arg == flagsTerminator && cmd.gnuFlags() {
+			flagsEnded = true
+			continue
 		}
 
-		if candidate == "" {
+		if IsFlag(arg) {
+			consumed := 1
+			if parseFlags {
+				var err error
+				consumed, err = cmd.parseFlag(args[i:])
+				if err != nil {
+					return cmd, err
+				}
+			} else if cmd.flagTakesValue(arg) {
+				consumed = 2
+			}
+			i += consumed - 1
 			continue
 		}
 
-		for _, command := range cmd.Commands() {
-			if command.Name == candidate {
-				command.arguments = cmd.arguments[offsetArgs:]
+		// Sub-commands are matched until the first positional argument
+		if len(positionals) == 0 {
+			command, err := cmd.findCommand(arg)
+			if err != nil {
+				return cmd, err
+			}
+			if command != nil {
 				cmd = command
+				continue
 			}
 		}
+
+		if len(cmd.commands) > 0 && !cmd.acceptsArgs() {
+			return cmd, &UnknownCommandError{
+				Command:     cmd,
+				Name:        arg,
+				Suggestions: cmd.commandSuggestions(arg),
+			}
+		}
+
+		positionals = append(positionals, arg)
+		if cmd.ParseMode == ParseStopAtFirstArg {
+			flagsEnded = true
+		}
 	}
 
-	return cmd
+	cmd.arguments = positionals
+
+	return cmd, nil
 }
 
-// ParseFlags ...
-func (c *Command) ParseFlags(args []string) *Command {
-	for _, arg := range args {
-		switch {
-		// A flag without a value, or with an `=` separated value
-		case IsFlag(arg):
-			for _, flag := range c.Flags() {
-				if flag.ShortName == arg {
-					flag.Parsed = true
-				}
-				if flag.LongName == arg {
-					flag.Parsed = true
-				}
-			}
-			continue
+// parseFlag parses the flag at the start of the arguments, and returns the
+// number of arguments it consumed.
+func (c *Command) parseFlag(args []string) (int, error) {
+	if c.gnuFlags() {
+		return c.parseGNUFlag(args)
+	}
+
+	name, value, hasValue := splitFlag(args[0])
+
+	flag := c.FlagName(name)
+	if flag == nil {
+		return 1, &UnknownFlagError{
+			Command:     c,
+			Flag:        name,
+			Suggestions: c.flagSuggestions(name),
 		}
 	}
 
-	return c
+	consumed := 1
+	switch {
+	// A flag with an `=` separated value
+	case hasValue:
+	// A boolean flag without a value
+	case flag.isBoolFlag():
+		value = "true"
+	// A flag with a space separated value
+	case len(args) > 1:
+		consumed = 2
+		value = args[1]
+	// A flag that requires a value but has none
+	default:
+		return consumed, &MissingFlagValueError{Command: c, Flag: name}
+	}
+
+	return consumed, c.setFlag(flag, name, value)
+}
+
+// setFlag sets the value of a flag given on the command line as name.
+//
+// An *InvalidFlagValueError is returned if the value can not be set.
+func (c *Command) setFlag(flag *Flag, name string, value string) error {
+	err := flag.Set(value)
+	if err != nil {
+		return &InvalidFlagValueError{Command: c, Flag: name, Value: value, Err: err}
+	}
+	flag.Parsed = true
+	flag.source = sourceCommandLine
+
+	return nil
+}
+
+// valueFlag returns the flag of this command whose value is given by arg, or
+// nil if there is none. In the GNU flag syntax it is the last of the short
+// flags of arg.
+func (c *Command) valueFlag(arg string) *Flag {
+	name, _, _ := splitFlag(arg)
+	if !c.gnuFlags() {
+		return c.FlagName(name)
+	}
+
+	if strings.HasPrefix(name, "--") {
+		return c.longFlag(name)
+	}
+
+	shorts := []rune(trimDashes(name))
+	if len(shorts) == 0 {
+		return nil
+	}
+
+	return c.shortFlag(shorts[len(shorts)-1])
+}
+
+// flagTakesValue checks if arg is a flag of this command that will consume
+// the next argument as its value.
+func (c *Command) flagTakesValue(arg string) bool {
+	if c.gnuFlags() {
+		return c.gnuFlagTakesValue(arg)
+	}
+
+	name, _, hasValue := splitFlag(arg)
+	if hasValue {
+		return false
+	}
+
+	flag := c.FlagName(name)
+
+	return flag != nil && !flag.isBoolFlag()
 }
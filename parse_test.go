@@ -18,14 +18,16 @@
 package cli_test
 
 import (
-	"os"
+	"bytes"
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/goombaio/cli"
 )
 
 func TestCommand_ParseCommands(t *testing.T) {
-	os.Args = []string{"programName"}
+	args := []string{}
 
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
@@ -34,11 +36,14 @@ func TestCommand_ParseCommands(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	_ = rootCommand.ParseCommands(os.Args)
+	_, err := rootCommand.ParseCommands(args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
 }
 
 func TestCommand_ParseCommands_withArguments(t *testing.T) {
-	os.Args = []string{"programName", "argument1"}
+	args := []string{"argument1"}
 
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
@@ -47,11 +52,21 @@ func TestCommand_ParseCommands_withArguments(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	_ = rootCommand.ParseCommands(os.Args)
+	_, err := rootCommand.ParseCommands(args)
+	var cmdErr *cli.UnknownCommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected *cli.UnknownCommandError but got %#v", err)
+	}
+	if cmdErr.Name != "argument1" {
+		t.Fatalf("Expected %q but got %q", "argument1", cmdErr.Name)
+	}
+	if cmdErr.Command != rootCommand {
+		t.Fatalf("Expected %s but got %s", rootCommand.Name, cmdErr.Command.Name)
+	}
 }
 
 func TestCommand_ParseCommands_withArguments_withSubCommands(t *testing.T) {
-	os.Args = []string{"programName", "argument1", "subCommand1", "argument2"}
+	args := []string{"argument1", "subCommand1", "argument2"}
 
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
@@ -60,11 +75,92 @@ func TestCommand_ParseCommands_withArguments_withSubCommands(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	_ = rootCommand.ParseCommands(os.Args)
+	_, err := rootCommand.ParseCommands(args)
+	var cmdErr *cli.UnknownCommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected *cli.UnknownCommandError but got %#v", err)
+	}
+	if cmdErr.Name != "argument1" {
+		t.Fatalf("Expected %q but got %q", "argument1", cmdErr.Name)
+	}
+	if cmdErr.Command != rootCommand {
+		t.Fatalf("Expected %s but got %s", rootCommand.Name, cmdErr.Command.Name)
+	}
+}
+
+func TestCommand_ParseCommands_aliases(t *testing.T) {
+	testCases := []struct {
+		args           []string
+		prefixMatching bool
+		expected       string
+	}{
+		{[]string{"remove"}, false, "remove"},
+		{[]string{"rm"}, false, "remove"},
+		{[]string{"delete"}, false, "remove"},
+		{[]string{"rem"}, true, "remove"},
+		{[]string{"del"}, true, "remove"},
+		{[]string{"rest"}, true, "restart"},
+		{[]string{"restart"}, true, "restart"},
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+
+	removeCommand := cli.NewCommand("remove", "remove Description")
+	removeCommand.Aliases = []string{"rm", "delete"}
+	rootCommand.AddCommand(removeCommand)
+	rootCommand.AddCommand(cli.NewCommand("restart", "restart Description"))
+
+	for _, tc := range testCases {
+		rootCommand.PrefixMatching = tc.prefixMatching
+
+		cmd, err := rootCommand.ParseCommands(tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+		if cmd.Name != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, cmd.Name, tc.args)
+		}
+	}
+}
+
+func TestCommand_ParseCommands_prefixWithoutPrefixMatching(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+
+	removeCommand := cli.NewCommand("remove", "remove Description")
+	removeCommand.Aliases = []string{"rm", "delete"}
+	rootCommand.AddCommand(removeCommand)
+	rootCommand.AddCommand(cli.NewCommand("restart", "restart Description"))
+
+	_, err := rootCommand.ParseCommands([]string{"rem"})
+	var cmdErr *cli.UnknownCommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected *cli.UnknownCommandError but got %#v", err)
+	}
+}
+
+func TestCommand_ParseCommands_ambiguousPrefix(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+
+	removeCommand := cli.NewCommand("remove", "remove Description")
+	removeCommand.Aliases = []string{"rm", "delete"}
+	rootCommand.AddCommand(removeCommand)
+	rootCommand.AddCommand(cli.NewCommand("restart", "restart Description"))
+	rootCommand.PrefixMatching = true
+
+	_, err := rootCommand.ParseCommands([]string{"re"})
+	var cmdErr *cli.AmbiguousCommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected *cli.AmbiguousCommandError but got %#v", err)
+	}
+
+	expected := `ambiguous command "re" for "programName", could be: remove, restart`
+	if err.Error() != expected {
+		t.Fatalf("Expected %q but got %q", expected, err.Error())
+	}
 }
 
 func TestCommand_ParseCommands_withFlags(t *testing.T) {
-	os.Args = []string{"programName", "-flag1", "-flag2"}
+	args := []string{"-flag1", "-flag2"}
 
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
@@ -73,11 +169,14 @@ func TestCommand_ParseCommands_withFlags(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	_ = rootCommand.ParseCommands(os.Args)
+	_, err := rootCommand.ParseCommands(args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
 }
 
 func TestCommand_ParseCommands_withFlags_withSubCommands(t *testing.T) {
-	os.Args = []string{"programName", "-flag1", "subCommand1", "-flag2"}
+	args := []string{"-flag1", "subCommand1", "-flag2"}
 
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
@@ -86,11 +185,14 @@ func TestCommand_ParseCommands_withFlags_withSubCommands(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	_ = rootCommand.ParseCommands(os.Args)
+	_, err := rootCommand.ParseCommands(args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
 }
 
 func TestCommand_ParseFlags_shortFlag(t *testing.T) {
-	os.Args = []string{"programName", "-h"}
+	args := []string{"-h"}
 
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
@@ -99,12 +201,19 @@ func TestCommand_ParseFlags_shortFlag(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	cmd := rootCommand.ParseCommands(os.Args)
-	_ = cmd.ParseFlags(os.Args)
+	cmd, err := rootCommand.ParseCommands(args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	_, err = cmd.ParseFlags(args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
 }
 
 func TestCommand_ParseFlags_longFlag(t *testing.T) {
-	os.Args = []string{"programName", "-help"}
+	args := []string{"-help"}
 
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
@@ -113,6 +222,298 @@ func TestCommand_ParseFlags_longFlag(t *testing.T) {
 	subCommand1.LongDescription = "subCommand1 Long Description"
 	rootCommand.AddCommand(subCommand1)
 
-	cmd := rootCommand.ParseCommands(os.Args)
-	_ = cmd.ParseFlags(os.Args)
+	cmd, err := rootCommand.ParseCommands(args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	_, err = cmd.ParseFlags(args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+}
+
+func TestCommand_ParseFlags_values(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-name=foo"}, "foo"},
+		{[]string{"--name=foo"}, "foo"},
+		{[]string{"-name", "foo"}, "foo"},
+		{[]string{"--name", "foo"}, "foo"},
+		{[]string{"-n", "foo"}, "foo"},
+		{[]string{"-n=foo"}, "foo"},
+		{[]string{"-name="}, ""},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.AddFlag(&cli.Flag{
+			ShortName:   "-n",
+			LongName:    "-name",
+			Description: "Name",
+			Value:       "default",
+		})
+
+		cmd, err := rootCommand.ParseFlags(tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		flag := cmd.FlagName("-name")
+		if !flag.Parsed {
+			t.Fatalf("Expected flag to be parsed for %q", tc.args)
+		}
+		if flag.Value != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, flag.Value, tc.args)
+		}
+	}
+}
+
+func TestCommand_ParseFlags_boolean(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-v"}, "true"},
+		{[]string{"--verbose"}, "true"},
+		{[]string{"-v=false"}, "false"},
+		{[]string{"-v", "argument1"}, "true"},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.AddFlag(&cli.Flag{
+			ShortName:   "-v",
+			LongName:    "-verbose",
+			Description: "Verbose output",
+			Value:       "false",
+		})
+
+		cmd, err := rootCommand.ParseFlags(tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		flag := cmd.FlagName("verbose")
+		if !flag.Parsed {
+			t.Fatalf("Expected flag to be parsed for %q", tc.args)
+		}
+		if flag.Value != tc.expected {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, flag.Value, tc.args)
+		}
+	}
+}
+
+func TestCommand_ParseFlags_missingValue(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{
+		ShortName:   "-n",
+		LongName:    "-name",
+		Description: "Name",
+		Value:       "default",
+	})
+
+	cmd, err := rootCommand.ParseFlags([]string{"-name"})
+	var flagErr *cli.MissingFlagValueError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.MissingFlagValueError but got %#v", err)
+	}
+
+	flag := cmd.FlagName("-name")
+	if flag.Parsed {
+		t.Fatalf("Expected flag not to be parsed")
+	}
+	if flag.Value != "default" {
+		t.Fatalf("Expected %q but got %q", "default", flag.Value)
+	}
+}
+
+func TestCommand_ParseCommands_flagValueIsNotACommand(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{
+		ShortName:   "-n",
+		LongName:    "-name",
+		Description: "Name",
+		Value:       "default",
+	})
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	rootCommand.AddCommand(subCommand1)
+
+	cmd, err := rootCommand.ParseCommands([]string{"-name", "subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if cmd != rootCommand {
+		t.Fatalf("Expected %s but got %s", rootCommand.Name, cmd.Name)
+	}
+
+	cmd, err = rootCommand.ParseCommands([]string{"-name=foo", "subCommand1", "argument1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if cmd != subCommand1 {
+		t.Fatalf("Expected %s but got %s", subCommand1.Name, cmd.Name)
+	}
+	if len(cmd.Arguments()) != 1 || cmd.Argument(0) != "argument1" {
+		t.Fatalf("Expected [argument1] but got %q", cmd.Arguments())
+	}
+}
+
+func TestCommand_ParseFlags_unknownFlag(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+
+	_, err := rootCommand.ParseFlags([]string{"-undefined=foo"})
+	var flagErr *cli.UnknownFlagError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.UnknownFlagError but got %#v", err)
+	}
+	if flagErr.Flag != "-undefined" {
+		t.Fatalf("Expected %q but got %q", "-undefined", flagErr.Flag)
+	}
+}
+
+func TestCommand_ParseFlags_invalidValue(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Int("-p", "-port", 80, "Port")
+
+	_, err := rootCommand.ParseFlags([]string{"-port", "http"})
+	var flagErr *cli.InvalidFlagValueError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.InvalidFlagValueError but got %#v", err)
+	}
+	if flagErr.Value != "http" {
+		t.Fatalf("Expected %q but got %q", "http", flagErr.Value)
+	}
+	if !errors.Is(err, flagErr.Err) {
+		t.Fatalf("Expected error to wrap %s", flagErr.Err)
+	}
+}
+
+func TestCommand_ParseFlags_segments(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootOutput := rootCommand.String("-o", "-output", "", "Output file")
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subOutput := subCommand1.String("-o", "-output", "", "Output file")
+	rootCommand.AddCommand(subCommand1)
+
+	cmd, err := rootCommand.ParseFlags([]string{"-o", "root.txt", "subCommand1", "-o=sub.txt"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if cmd != subCommand1 {
+		t.Fatalf("Expected %q but got %q", subCommand1.Name, cmd.Name)
+	}
+	if *rootOutput != "root.txt" || *subOutput != "sub.txt" {
+		t.Fatalf("Expected %q and %q but got %q and %q", "root.txt", "sub.txt", *rootOutput, *subOutput)
+	}
+}
+
+func TestCommand_ParseFlags_segments_unknownFlag(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	rootCommand.AddCommand(subCommand1)
+
+	_, err := rootCommand.ParseFlags([]string{"-v", "subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	_, err = rootCommand.ParseFlags([]string{"subCommand1", "-v"})
+	var flagErr *cli.UnknownFlagError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("Expected *cli.UnknownFlagError but got %#v", err)
+	}
+	if flagErr.Command != subCommand1 {
+		t.Fatalf("Expected %q but got %q", subCommand1.Name, flagErr.Command.Name)
+	}
+	expected := "unknown flag -v for \"programName subCommand1\""
+	if err.Error() != expected {
+		t.Fatalf("Expected %q but got %q", expected, err.Error())
+	}
+}
+
+func TestCommand_Execute_parentFlags(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetOutput(buf)
+	rootCommand.AddFlag(&cli.Flag{LongName: "-mode", Description: "Mode", Validators: []cli.FlagValidator{cli.OneOf("a", "b")}})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-old", Description: "Old flag", Value: "false", Deprecated: "use -mode instead"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-name", Description: "Name", EnvVars: []string{"PROGRAMNAME_NAME"}})
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-mode", "zzz", "subCommand1"})
+	var validationErr *cli.FlagValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *cli.FlagValidationError but got %#v", err)
+	}
+
+	err = cli.ExecuteArgs(rootCommand, []string{"-old", "subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	expected := "warning: flag -old is deprecated, use -mode instead\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected %q but got %q", expected, buf.String())
+	}
+
+	t.Setenv("PROGRAMNAME_NAME", "foo")
+	err = cli.ExecuteArgs(rootCommand, []string{"subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if value := rootCommand.FlagName("-name").Value; value != "foo" {
+		t.Fatalf("Expected %q but got %q", "foo", value)
+	}
+
+	rootCommand.FlagName("-mode").Required = true
+	err = cli.ExecuteArgs(rootCommand, []string{"subCommand1"})
+	var requiredErr *cli.RequiredFlagError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 || !errors.As(validationErr.Errors[0], &requiredErr) {
+		t.Fatalf("Expected *cli.RequiredFlagError but got %#v", err)
+	}
+	if requiredErr.Command != rootCommand {
+		t.Fatalf("Expected %q but got %q", rootCommand.Name, requiredErr.Command.Name)
+	}
+}
+
+func TestCommand_ParseFlags_parseMode(t *testing.T) {
+	testCases := []struct {
+		mode        cli.ParseMode
+		args        []string
+		verbose     bool
+		positionals []string
+	}{
+		{cli.ParseInterspersed, []string{"run", "a", "-v", "b"}, true, []string{"a", "b"}},
+		{cli.ParseStopAtFirstArg, []string{"run", "-v", "ls", "-l", "-v"}, true, []string{"ls", "-l", "-v"}},
+		{cli.ParseStopAtFirstArg, []string{"run", "ls", "-v"}, false, []string{"ls", "-v"}},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+
+		runCommand := cli.NewCommand("run", "run Description")
+		runCommand.ParseMode = tc.mode
+		runCommand.ValidateArgs = cli.MinimumArgs(1)
+		verbose := runCommand.Bool("-v", "-verbose", false, "Verbose output")
+		rootCommand.AddCommand(runCommand)
+
+		cmd, err := rootCommand.ParseFlags(tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s for %q", err, tc.args)
+		}
+		if *verbose != tc.verbose {
+			t.Fatalf("Expected %v but got %v for %q", tc.verbose, *verbose, tc.args)
+		}
+		if !reflect.DeepEqual(cmd.Arguments(), tc.positionals) {
+			t.Fatalf("Expected %q but got %q for %q", tc.positionals, cmd.Arguments(), tc.args)
+		}
+	}
 }
@@ -0,0 +1,80 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/goombaio/cli"
+)
+
+func TestSignalContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending signals to the own process is not supported on windows")
+	}
+
+	ctx, stop := cli.SignalContext(context.Background())
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	err = process.Signal(os.Interrupt)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected context to be cancelled")
+	}
+}
+
+func TestSignalContext_stop(t *testing.T) {
+	ctx, stop := cli.SignalContext(context.Background())
+	stop()
+	stop()
+
+	if ctx.Err() != context.Canceled {
+		t.Fatalf("Expected %s but got %v", context.Canceled, ctx.Err())
+	}
+}
+
+func TestCommand_HandleSignals(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.HandleSignals = true
+	rootCommand.RunContext = func(ctx context.Context, c *cli.Command) error {
+		if ctx.Done() == nil {
+			t.Fatalf("Expected a cancellable context")
+		}
+
+		return nil
+	}
+
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// maxSuggestionDistance is the maximum edit distance between a mistyped
+	// name and a candidate for the candidate to be suggested.
+	maxSuggestionDistance = 2
+)
+
+// suggestions returns the candidates that are close to name, sorted from the
+// closest to the farthest.
+//
+// A candidate is close to name when their edit distance is small compared to
+// the length of the candidate, or when name is a prefix of the candidate.
+func suggestions(name string, candidates []string) []string {
+	type suggestion struct {
+		candidate string
+		distance  int
+	}
+
+	name = strings.ToLower(name)
+
+	found := make([]suggestion, 0)
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if candidate == "" || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		lower := strings.ToLower(candidate)
+
+		maxDistance := len(lower) / 2
+		if maxDistance > maxSuggestionDistance {
+			maxDistance = maxSuggestionDistance
+		}
+
+		distance := levenshtein(name, lower)
+		if distance <= maxDistance || (len(name) > 1 && strings.HasPrefix(lower, name)) {
+			found = append(found, suggestion{candidate, distance})
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].distance < found[j].distance
+	})
+
+	result := make([]string, 0, len(found))
+	for _, s := range found {
+		result = append(result, s.candidate)
+	}
+
+	return result
+}
+
+// commandSuggestions returns the names of the subcommands of this command that
+// are close to name.
+func (c *Command) commandSuggestions(name string) []string {
+	candidates := make([]string, 0, len(c.commands))
+	for _, command := range c.Commands() {
+		if command.visible() {
+			candidates = append(candidates, command.Name)
+			candidates = append(candidates, command.Aliases...)
+		}
+	}
+
+	return suggestions(name, candidates)
+}
+
+// flagSuggestions returns the names of the flags of this command, including
+// the inherited ones, that are close to name.
+//
+// Names are compared without their leading dashes, and are suggested as they
+// were defined.
+func (c *Command) flagSuggestions(name string) []string {
+	byName := make(map[string]string)
+	candidates := make([]string, 0)
+	for _, flag := range c.availableFlags() {
+		if !flag.visible() {
+			continue
+		}
+		for _, flagName := range []string{flag.LongName, flag.ShortName} {
+			trimmed := trimDashes(flagName)
+			if _, ok := byName[trimmed]; ok || trimmed == "" {
+				continue
+			}
+			byName[trimmed] = flagName
+			candidates = append(candidates, trimmed)
+		}
+	}
+
+	result := suggestions(trimDashes(name), candidates)
+	for i, candidate := range result {
+		result[i] = byName[candidate]
+	}
+
+	return result
+}
+
+// levenshtein returns the edit distance between the strings a and b.
+func levenshtein(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// min3 returns the minimum of three ints.
+func min3(a int, b int, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+
+	return m
+}
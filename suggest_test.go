@@ -0,0 +1,93 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_ParseCommands_suggestions(t *testing.T) {
+	testCases := []struct {
+		arg      string
+		expected []string
+	}{
+		{"delpoy", []string{"deploy"}},
+		{"Deploy", []string{"deploy"}},
+		{"dep", []string{"deploy"}},
+		{"statsu", []string{"stats", "status"}},
+		{"foo", []string{}},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.AddCommand(cli.NewCommand("deploy", "deploy Description"))
+		rootCommand.AddCommand(cli.NewCommand("stats", "stats Description"))
+		rootCommand.AddCommand(cli.NewCommand("status", "status Description"))
+
+		_, err := rootCommand.ParseCommands([]string{tc.arg})
+		var cmdErr *cli.UnknownCommandError
+		if !errors.As(err, &cmdErr) {
+			t.Fatalf("Expected *cli.UnknownCommandError but got %#v", err)
+		}
+		if !reflect.DeepEqual(cmdErr.Suggestions, tc.expected) {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, cmdErr.Suggestions, tc.arg)
+		}
+	}
+}
+
+func TestCommand_ParseFlags_suggestions(t *testing.T) {
+	testCases := []struct {
+		arg      string
+		expected []string
+	}{
+		{"-verbos", []string{"-verbose"}},
+		{"--verbsoe", []string{"-verbose"}},
+		{"-hlep", []string{"-help"}},
+		{"-x", []string{}},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+
+		_, err := rootCommand.ParseFlags([]string{tc.arg})
+		var flagErr *cli.UnknownFlagError
+		if !errors.As(err, &flagErr) {
+			t.Fatalf("Expected *cli.UnknownFlagError but got %#v", err)
+		}
+		if !reflect.DeepEqual(flagErr.Suggestions, tc.expected) {
+			t.Fatalf("Expected %q but got %q for %q", tc.expected, flagErr.Suggestions, tc.arg)
+		}
+	}
+}
+
+func TestUnknownCommandError_Error(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddCommand(cli.NewCommand("deploy", "deploy Description"))
+
+	_, err := rootCommand.ParseCommands([]string{"delpoy"})
+
+	expected := "unknown command \"delpoy\" for \"programName\"\n\nDid you mean this?\n\tdeploy"
+	if err.Error() != expected {
+		t.Fatalf("Expected %q but got %q", expected, err.Error())
+	}
+}
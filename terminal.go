@@ -0,0 +1,47 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// defaultTerminalWidth is the width the usage output is wrapped to when the
+// width of the terminal is unknown.
+const defaultTerminalWidth = 80
+
+// terminalWidth returns the width, in columns, the output written to w is
+// wrapped to.
+//
+// It is the value of the environment variable COLUMNS if set, else the width
+// of the terminal when w is one, else defaultTerminalWidth.
+func terminalWidth(w io.Writer) int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	if f, ok := w.(*os.File); ok {
+		if width := fileWidth(f); width > 0 {
+			return width
+		}
+	}
+
+	return defaultTerminalWidth
+}
@@ -18,72 +18,499 @@
 package cli
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
 	"text/template"
+	"unicode/utf8"
 )
 
 const (
 	// UsageTemplate is the template being used to render the Usage for
 	// any cli.Command that has a flag -h or-help attached to it.
-	UsageTemplate = `usage: {{.Name}} [-help] <command> [args]{{if .LongDescription}}
+	//
+	// The entries of every section are aligned in two columns, the second
+	// one wrapped to the width of the terminal. The hint about the help of
+	// the sub-commands is only shown for commands that have them.
+	UsageTemplate = `usage: {{.Path}} [{{.HelpFlag}}]{{.ArgumentsUsage}}{{if .LongDescription}}
 
-  {{.LongDescription}}{{end}}
-{{if .Commands}}
-Commands:
-{{range .Commands}}  {{.Name}}        {{.ShortDescription}}
+  {{wrap 2 .LongDescription}}{{end}}
+{{if .Aliases}}
+Aliases:
+  {{join .Aliases ", "}}
+{{end}}{{if .Example}}
+Examples:
+{{.Example}}
+{{end}}{{range .CommandGroups}}
+{{.Title}}:
+{{range .Commands}}  {{rpad .Names $.Padding}}  {{wrap $.Indent .ShortDescription}}
+{{end}}{{end}}{{if .HelpTopics}}
+Help Topics:
+{{range .HelpTopics}}  {{rpad .Names $.Padding}}  {{wrap $.Indent .ShortDescription}}
+{{end}}{{end}}{{if .Arguments}}
+Arguments:
+{{range .Arguments}}  {{rpad .Name $.Padding}}  {{wrap $.Indent .Description}}
 {{end}}{{end}}{{if .Flags}}
 Flags:
-{{range .Flags}}  {{.ShortName}}, {{.LongName}}	{{.Description}}
-{{end}}{{end}}
-Use {{.Name}} [command] -help for more information about a command.
-`
+{{range .Flags}}  {{rpad .Names $.Padding}}  {{wrap $.Indent .Usage}}
+{{end}}{{end}}{{if .FlagGroups}}
+Flag Groups:
+{{range .FlagGroups}}  {{rpad .Flags $.Padding}}  {{wrap $.Indent .Kind}}
+{{end}}{{end}}{{if .InheritedFlags}}
+Global Flags:
+{{range .InheritedFlags}}  {{rpad .Names $.Padding}}  {{wrap $.Indent .Usage}}
+{{end}}{{end}}{{if .Commands}}
+Use {{.Path}} [command] {{.HelpFlag}} for more information about a command.
+{{end}}`
 )
 
+// UsageData is the data the usage template of a command is rendered with.
+type UsageData struct {
+	// Command is the command the usage is rendered for.
+	Command *Command
+
+	// Name is the name of the command.
+	Name string
+
+	// Path is the names of the commands from the root to the command, space
+	// separated.
+	Path string
+
+	// ParentPath is the path of the parent of the command, empty for the
+	// root command.
+	ParentPath string
+
+	ShortDescription string
+	LongDescription  string
+	Example          string
+	Aliases          []string
+
+	// HelpFlag is the name of the help flag, as written in the flag syntax
+	// of the command.
+	HelpFlag string
+
+	// ArgumentsUsage is the representation of the sub-commands and the
+	// positional arguments of the command in the usage line.
+	ArgumentsUsage string
+
+	Commands        []UsageCommand
+	CommandGroups   []UsageCommandGroup
+	HelpTopics      []UsageCommand
+	Arguments       []UsageArgument
+	Flags           []UsageFlag
+	PersistentFlags []UsageFlag
+	FlagGroups      []UsageFlagGroup
+	InheritedFlags  []UsageFlag
+
+	// Width is the width, in columns, of the terminal the usage is written
+	// to.
+	Width int
+
+	// Padding is the width of the first column of the sections, the longest
+	// of the command names, the help topic names, the argument names, the
+	// flag names and the flag groups.
+	Padding int
+
+	// Indent is the column the second column of the sections starts at.
+	Indent int
+}
+
+// UsageCommand is a sub-command in the usage template data.
+type UsageCommand struct {
+	Name             string
+	ShortDescription string
+	Aliases          []string
+
+	// Names is the name of the command followed by its aliases, comma
+	// separated.
+	Names string
+
+	// Group is the group of the command, empty if it is ungrouped.
+	Group string
+}
+
+// UsageCommandGroup is a group of sub-commands in the usage template data.
+type UsageCommandGroup struct {
+	// Title is the heading of the group.
+	Title string
+
+	Commands []UsageCommand
+}
+
+// UsageArgument is a declared positional argument in the usage template data.
+type UsageArgument struct {
+	Name        string
+	Description string
+	Required    bool
+	Variadic    bool
+}
+
+// UsageFlag is a flag in the usage template data.
+type UsageFlag struct {
+	ShortName   string
+	LongName    string
+	Description string
+	Default     string
+	Required    bool
+
+	// Env is the list of the environment variables bound to the flag, comma
+	// separated.
+	Env string
+
+	// Names is the list of the names of the flag, comma separated.
+	Names string
+
+	// Usage is the description of the flag followed by its default value,
+	// whether it is required and its environment variables.
+	Usage string
+}
+
+// UsageFlagGroup is a flag group in the usage template data.
+type UsageFlagGroup struct {
+	// Flags is the list of the names of the flags in the group, comma
+	// separated.
+	Flags string
+
+	// Kind is the constraint of the group.
+	Kind string
+}
+
 // Usage puts out the usage for the command.
 //
 // It is used when a user provides invalid input or when the flag -h or -help
 // is attached in the input.
+//
+// The usage is rendered with the template of the command, see
+// SetUsageTemplate, and the UsageData of the command. An error rendering the
+// template is written to the output instead.
 func (c *Command) Usage() {
-	templateData := struct {
-		Name            string
-		LongDescription string
-		Commands        []struct {
-			Name             string
-			ShortDescription string
+	err := c.usage()
+	if err != nil {
+		fmt.Fprintf(c.Output(), "error: %s\n", err)
+	}
+}
+
+// usage puts out the usage for the command, and returns the error found
+// parsing or executing its template, if any.
+func (c *Command) usage() error {
+	t, err := template.New("usageTemplate").Funcs(c.usageTemplateFuncs()).Parse(c.UsageTemplate())
+	if err != nil {
+		return fmt.Errorf("usage template of %q: %w", c.CommandPath(), err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = t.Execute(buf, c.usageData())
+	if err != nil {
+		return fmt.Errorf("usage template of %q: %w", c.CommandPath(), err)
+	}
+
+	// Entries without a description leave the padding of their first column
+	lines := strings.Split(buf.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	_, _ = io.WriteString(c.Output(), strings.Join(lines, "\n"))
+
+	return nil
+}
+
+// UsageTemplate returns the usage template of this command.
+//
+// It is the one set on this command or on its nearest parent, or the default
+// UsageTemplate.
+func (c *Command) UsageTemplate() string {
+	for command := c; command != nil; command = command.parent {
+		if command.usageTemplate != "" {
+			return command.usageTemplate
 		}
-		Flags []struct {
-			ShortName   string
-			LongName    string
-			Description string
+	}
+
+	return UsageTemplate
+}
+
+// SetUsageTemplate sets the usage template of this command and of its
+// sub-commands that do not set their own.
+//
+// The template is rendered with the UsageData of the command, and it can use
+// the functions:
+//
+//	join a sep   the elements of a joined with the separator sep
+//	rpad s n     s padded with spaces to the width n
+//	wrap n s     s wrapped to the width of the terminal, with every line but
+//	             the first one indented by n spaces
+//
+// along with the ones added with AddTemplateFuncs.
+//
+// The template is parsed when the usage is rendered, so the functions can be
+// added afterwards. An invalid template makes the -help flag and the help
+// command return the error found.
+func (c *Command) SetUsageTemplate(tmpl string) {
+	c.usageTemplate = tmpl
+}
+
+// AddTemplateFuncs adds functions to the usage template of this command and
+// of its sub-commands.
+//
+// A function added on a sub-command takes precedence over a function with
+// the same name added on a parent.
+func (c *Command) AddTemplateFuncs(funcs template.FuncMap) {
+	if c.templateFuncs == nil {
+		c.templateFuncs = make(template.FuncMap)
+	}
+
+	for name, fn := range funcs {
+		c.templateFuncs[name] = fn
+	}
+}
+
+// usageTemplateFuncs returns the template functions of this command,
+// including the ones added on its parents.
+func (c *Command) usageTemplateFuncs() template.FuncMap {
+	width := terminalWidth(c.Output())
+
+	funcs := template.FuncMap{
+		"join": strings.Join,
+		"rpad": rpad,
+		"wrap": func(indent int, s string) string {
+			return wrap(s, indent, width)
+		},
+	}
+	for _, command := range c.path() {
+		for name, fn := range command.templateFuncs {
+			funcs[name] = fn
 		}
-	}{
-		Name:            c.Name,
-		LongDescription: c.LongDescription,
+	}
+
+	return funcs
+}
+
+// usageData returns the data the usage template of this command is rendered
+// with.
+func (c *Command) usageData() UsageData {
+	parentPath := ""
+	if c.parent != nil {
+		parentPath = c.parent.CommandPath()
+	}
+
+	data := UsageData{
+		Command:          c,
+		Name:             c.Name,
+		Path:             c.CommandPath(),
+		ParentPath:       parentPath,
+		ShortDescription: c.ShortDescription,
+		LongDescription:  c.LongDescription,
+		Example:          c.Example,
+		Aliases:          c.Aliases,
+		ArgumentsUsage:   c.argsUsage(),
+		HelpFlag:         "-help",
+		Flags:            usageFlags(c.flags, c.gnuFlags()),
+		PersistentFlags:  usageFlags(c.PersistentFlags(), c.gnuFlags()),
+		InheritedFlags:   usageFlags(c.InheritedFlags(), c.gnuFlags()),
+		Width:            terminalWidth(c.Output()),
+	}
+	if c.gnuFlags() {
+		data.HelpFlag = gnuFlagName(data.HelpFlag, true)
 	}
 
 	for _, subCommand := range c.commands {
-		subc := struct {
-			Name             string
-			ShortDescription string
-		}{
-			subCommand.Name,
-			subCommand.ShortDescription,
+		if !subCommand.visible() {
+			continue
+		}
+
+		usageCommand := UsageCommand{
+			Name:             subCommand.Name,
+			ShortDescription: subCommand.ShortDescription,
+			Aliases:          subCommand.Aliases,
+			Names:            strings.Join(append([]string{subCommand.Name}, subCommand.Aliases...), ", "),
+			Group:            subCommand.Group,
+		}
+		if subCommand.helpTopic {
+			data.HelpTopics = append(data.HelpTopics, usageCommand)
+			continue
+		}
+		data.Commands = append(data.Commands, usageCommand)
+	}
+	data.CommandGroups = c.usageCommandGroups(data.Commands)
+
+	for _, arg := range c.positionals {
+		data.Arguments = append(data.Arguments, UsageArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+			Variadic:    arg.Variadic,
+		})
+	}
+
+	for _, group := range c.flagGroups {
+		data.FlagGroups = append(data.FlagGroups, UsageFlagGroup{
+			Flags: strings.Join(group.names, ", "),
+			Kind:  group.kind.String(),
+		})
+	}
+
+	columns := make([]string, 0)
+	for _, command := range data.Commands {
+		columns = append(columns, command.Names)
+	}
+	for _, topic := range data.HelpTopics {
+		columns = append(columns, topic.Names)
+	}
+	for _, arg := range data.Arguments {
+		columns = append(columns, arg.Name)
+	}
+	for _, flag := range data.Flags {
+		columns = append(columns, flag.Names)
+	}
+	for _, group := range data.FlagGroups {
+		columns = append(columns, group.Flags)
+	}
+	for _, flag := range data.InheritedFlags {
+		columns = append(columns, flag.Names)
+	}
+	for _, column := range columns {
+		if n := utf8.RuneCountInString(column); n > data.Padding {
+			data.Padding = n
+		}
+	}
+	data.Indent = data.Padding + 4
+
+	return data
+}
+
+// usageFlags returns the flags as shown in the usage template data, with
+// their names written in the GNU flag syntax if gnu is set.
+func usageFlags(flags []*Flag, gnu bool) []UsageFlag {
+	usage := make([]UsageFlag, 0, len(flags))
+	for _, flag := range flags {
+		if !flag.visible() {
+			continue
+		}
+
+		usage = append(usage, UsageFlag{
+			ShortName:   flag.ShortName,
+			LongName:    flag.LongName,
+			Description: flag.Description,
+			Default:     flagDefault(flag),
+			Required:    flag.Required,
+			Env:         flagEnvUsage(flag),
+			Names:       flagNamesUsage(flag, gnu),
+			Usage:       flagUsage(flag),
+		})
+	}
+
+	return usage
+}
+
+// flagNamesUsage returns the names of a flag as shown in the usage output,
+// written in the GNU flag syntax if gnu is set.
+func flagNamesUsage(flag *Flag, gnu bool) string {
+	names := make([]string, 0, 2)
+	if flag.ShortName != "" {
+		names = append(names, flag.ShortName)
+		if gnu {
+			names[len(names)-1] = gnuFlagName(flag.ShortName, false)
 		}
-		templateData.Commands = append(templateData.Commands, subc)
-	}
-
-	for _, flag := range c.flags {
-		subf := struct {
-			ShortName   string
-			LongName    string
-			Description string
-		}{
-			flag.ShortName,
-			flag.LongName,
-			flag.Description,
+	}
+	if flag.LongName != "" {
+		names = append(names, flag.LongName)
+		if gnu {
+			names[len(names)-1] = gnuFlagName(flag.LongName, true)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// flagUsage returns the description of a flag as shown in the usage output.
+func flagUsage(flag *Flag) string {
+	usage := flag.Description
+	if value := flagDefault(flag); value != "" {
+		usage += " (default " + value + ")"
+	} else if flag.Required {
+		usage += " (required)"
+	}
+	if env := flagEnvUsage(flag); env != "" {
+		usage += " [" + env + "]"
+	}
+
+	return strings.TrimSpace(usage)
+}
+
+// flagDefault returns the default value of a flag as shown in the usage
+// output, which is its Default or else the value it was added with, unless it
+// is the zero value of its type.
+func flagDefault(flag *Flag) string {
+	if flag.Default != "" {
+		return flag.Default
+	}
+
+	switch flag.initial {
+	case "false", "0", "0s":
+		return ""
+	}
+
+	return flag.initial
+}
+
+// flagEnvUsage returns the environment variables bound to a flag as shown in
+// the usage output.
+func flagEnvUsage(flag *Flag) string {
+	names := flag.Env()
+	for i, name := range names {
+		names[i] = "$" + name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// rpad pads s with spaces on the right to the width n.
+func rpad(s string, n int) string {
+	padding := n - utf8.RuneCountInString(s)
+	if padding <= 0 {
+		return s
+	}
+
+	return s + strings.Repeat(" ", padding)
+}
+
+// minWrapWidth is the minimum width text is wrapped to, narrower columns are
+// not wrapped.
+const minWrapWidth = 20
+
+// wrap wraps the lines of s to fit in a column that starts at the column
+// indent of a terminal width columns wide. The lines after the first one are
+// indented by indent spaces.
+func wrap(s string, indent int, width int) string {
+	width -= indent
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(s, "\n") {
+		words := strings.Fields(line)
+		if width < minWrapWidth || len(words) == 0 {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+			continue
+		}
+
+		current := words[0]
+		for _, word := range words[1:] {
+			if utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > width {
+				lines = append(lines, current)
+				current = word
+				continue
+			}
+			current += " " + word
+		}
+		lines = append(lines, current)
+	}
+
+	prefix := strings.Repeat(" ", indent)
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = prefix + lines[i]
 		}
-		templateData.Flags = append(templateData.Flags, subf)
 	}
 
-	t := template.Must(template.New("usageTemplate").Parse(UsageTemplate))
-	_ = t.Execute(c.Output(), templateData)
+	return strings.Join(lines, "\n")
 }
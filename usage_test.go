@@ -21,15 +21,15 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/goombaio/cli"
 	"github.com/goombaio/log"
 )
 
 func TestCommand_Usage(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -42,21 +42,19 @@ func TestCommand_Usage(t *testing.T) {
 	buf := new(bytes.Buffer)
 	rootCommand.SetOutput(buf)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 
-	expected := fmt.Sprintf("usage: %s [-help] <command> [args]\n", rootCommand.Name)
+	expected := fmt.Sprintf("usage: %s [-help] [args]\n", rootCommand.Name)
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("  %s\n", rootCommand.LongDescription)
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("Flags:\n")
 	for _, flag := range rootCommand.Flags() {
-		expected += fmt.Sprintf("  %s, %s	%s\n", flag.ShortName, flag.LongName, flag.Description)
+		expected += fmt.Sprintf("  %-9s  %s\n", flag.ShortName+", "+flag.LongName, flag.Description)
 	}
-	expected += fmt.Sprintf("\n")
-	expected += fmt.Sprintf("Use %s [command] -help for more information about a command.\n", rootCommand.Name)
 	if buf.String() != expected {
 		t.Fatalf("Expected %q but got %q", expected, buf.String())
 	}
@@ -79,29 +77,25 @@ func TestCommand_withoutConstructor_Usage(t *testing.T) {
 	buf := new(bytes.Buffer)
 	rootCommand.SetOutput(buf)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 
-	expected := fmt.Sprintf("usage: %s [-help] <command> [args]\n", rootCommand.Name)
+	expected := fmt.Sprintf("usage: %s [-help] [args]\n", rootCommand.Name)
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("  %s\n", rootCommand.LongDescription)
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("Flags:\n")
 	for _, flag := range rootCommand.Flags() {
-		expected += fmt.Sprintf("  %s, %s	%s\n", flag.ShortName, flag.LongName, flag.Description)
+		expected += fmt.Sprintf("  %-9s  %s\n", flag.ShortName+", "+flag.LongName, flag.Description)
 	}
-	expected += fmt.Sprintf("\n")
-	expected += fmt.Sprintf("Use %s [command] -help for more information about a command.\n", rootCommand.Name)
 	if buf.String() != expected {
 		t.Fatalf("Expected %q but got %q", expected, buf.String())
 	}
 }
 
 func TestCommand_Usage_withSubCommands(t *testing.T) {
-	os.Args = []string{"programName"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -123,23 +117,23 @@ func TestCommand_Usage_withSubCommands(t *testing.T) {
 	}
 	rootCommand.AddCommand(subCommand1)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 
-	expected := fmt.Sprintf("usage: %s [-help] <command> [args]\n", rootCommand.Name)
+	expected := fmt.Sprintf("usage: %s [-help] [command]\n", rootCommand.Name)
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("  %s\n", rootCommand.LongDescription)
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("Commands:\n")
 	for _, command := range rootCommand.Commands() {
-		expected += fmt.Sprintf("  %s        %s\n", command.Name, command.ShortDescription)
+		expected += fmt.Sprintf("  %-11s  %s\n", command.Name, command.ShortDescription)
 	}
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("Flags:\n")
 	for _, flag := range rootCommand.Flags() {
-		expected += fmt.Sprintf("  %s, %s	%s\n", flag.ShortName, flag.LongName, flag.Description)
+		expected += fmt.Sprintf("  %-11s  %s\n", flag.ShortName+", "+flag.LongName, flag.Description)
 	}
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("Use %s [command] -help for more information about a command.\n", rootCommand.Name)
@@ -149,8 +143,6 @@ func TestCommand_Usage_withSubCommands(t *testing.T) {
 }
 
 func TestCommand_Usage_subCommand(t *testing.T) {
-	os.Args = []string{"programName", "subCommand1"}
-
 	rootCommand := cli.NewCommand("programName", "rootCommand Description")
 	rootCommand.LongDescription = "rootCommand Long Description"
 	rootCommand.Run = func(c *cli.Command) error {
@@ -172,22 +164,221 @@ func TestCommand_Usage_subCommand(t *testing.T) {
 	}
 	rootCommand.AddCommand(subCommand1)
 
-	err := cli.Execute(rootCommand)
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1"})
 	if err != nil {
 		t.Fatalf("Expected no error but got %s", err)
 	}
 
-	expected := fmt.Sprintf("usage: %s [-help] <command> [args]\n", subCommand1.Name)
+	expected := fmt.Sprintf("usage: %s [-help] [args]\n", subCommand1.CommandPath())
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("  %s\n", subCommand1.LongDescription)
 	expected += fmt.Sprintf("\n")
 	expected += fmt.Sprintf("Flags:\n")
 	for _, flag := range subCommand1.Flags() {
-		expected += fmt.Sprintf("  %s, %s	%s\n", flag.ShortName, flag.LongName, flag.Description)
+		expected += fmt.Sprintf("  %-9s  %s\n", flag.ShortName+", "+flag.LongName, flag.Description)
+	}
+	if buf.String() != expected {
+		t.Fatalf("Expected %q but got %q", expected, buf.String())
+	}
+}
+
+func TestCommand_Usage_inheritedFlags(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddPersistentFlag(&cli.Flag{ShortName: "-v", LongName: "-verbose", Description: "Verbose output", Value: "false"})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.Run = func(c *cli.Command) error {
+		c.Usage()
+
+		return nil
+	}
+	rootCommand.AddCommand(subCommand1)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"subCommand1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
 	}
+
+	expected := fmt.Sprintf("usage: %s [-help] [args]\n", subCommand1.CommandPath())
 	expected += fmt.Sprintf("\n")
-	expected += fmt.Sprintf("Use %s [command] -help for more information about a command.\n", subCommand1.Name)
+	expected += fmt.Sprintf("Flags:\n")
+	expected += fmt.Sprintf("  -h, -help     Show help message\n")
+	expected += fmt.Sprintf("\n")
+	expected += fmt.Sprintf("Global Flags:\n")
+	expected += fmt.Sprintf("  -v, -verbose  Verbose output\n")
+	if buf.String() != expected {
+		t.Fatalf("Expected %q but got %q", expected, buf.String())
+	}
+}
+
+func TestCommand_SetUsageTemplate(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.SetUsageTemplate("{{upper .Path}} ({{.ParentPath}}): {{.ShortDescription}}{{range .Aliases}} {{.}}{{end}}\n{{.Example}}\n")
+	rootCommand.AddTemplateFuncs(template.FuncMap{"upper": strings.ToUpper})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	subCommand1 := cli.NewCommand("subCommand1", "subCommand1 Description")
+	subCommand1.Aliases = []string{"sc1"}
+	subCommand1.Example = "  programName subCommand1"
+	rootCommand.AddCommand(subCommand1)
+
+	subCommand2 := cli.NewCommand("subCommand2", "subCommand2 Description")
+	subCommand2.SetUsageTemplate("{{upper .Name}}\n")
+	subCommand2.AddTemplateFuncs(template.FuncMap{"upper": strings.ToLower})
+	rootCommand.AddCommand(subCommand2)
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-help"}, "PROGRAMNAME (): rootCommand Description\n\n"},
+		{[]string{"subCommand1", "-help"}, "PROGRAMNAME SUBCOMMAND1 (programName): subCommand1 Description sc1\n  programName subCommand1\n"},
+		{[]string{"subCommand2", "-help"}, "subcommand2\n"},
+	}
+
+	for _, tc := range testCases {
+		buf.Reset()
+
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		if buf.String() != tc.expected {
+			t.Fatalf("Expected %q but got %q", tc.expected, buf.String())
+		}
+	}
+}
+
+func TestCommand_SetUsageTemplate_invalid(t *testing.T) {
+	testCases := []struct {
+		tmpl     string
+		expected string
+	}{
+		{"{{.Path", "unclosed action"},
+		{"{{.Bogus}}", "can't evaluate field Bogus"},
+	}
+
+	for _, tc := range testCases {
+		rootCommand := cli.NewCommand("programName", "rootCommand Description")
+		rootCommand.SetUsageTemplate(tc.tmpl)
+
+		buf := new(bytes.Buffer)
+		rootCommand.SetOutput(buf)
+
+		err := cli.ExecuteArgs(rootCommand, []string{"-h"})
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf("Expected error containing %q but got %v for %q", tc.expected, err, tc.tmpl)
+		}
+
+		buf.Reset()
+		rootCommand.Usage()
+		if !strings.HasPrefix(buf.String(), "error: usage template of \"programName\": ") {
+			t.Fatalf("Expected the error in the output but got %q for %q", buf.String(), tc.tmpl)
+		}
+	}
+}
+
+func TestCommand_Usage_example(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Example = "  programName -help"
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	rootCommand.Usage()
+
+	expected := "usage: programName [-help] [args]\n"
+	expected += "\n"
+	expected += "Examples:\n"
+	expected += "  programName -help\n"
+	expected += "\n"
+	expected += "Flags:\n"
+	expected += "  -h, -help  Show help message\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected %q but got %q", expected, buf.String())
+	}
+}
+
+func TestCommand_Usage_wrap(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.LongDescription = "rootCommand Long Description that does not fit in one line"
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-o", LongName: "-output", Description: "Write the results to the file instead of the standard output", Default: "-"})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	rootCommand.Usage()
+
+	expected := "usage: programName [-help] [args]\n"
+	expected += "\n"
+	expected += "  rootCommand Long Description that does\n"
+	expected += "  not fit in one line\n"
+	expected += "\n"
+	expected += "Flags:\n"
+	expected += "  -h, -help    Show help message\n"
+	expected += "  -o, -output  Write the results to the\n"
+	expected += "               file instead of the\n"
+	expected += "               standard output (default\n"
+	expected += "               -)\n"
 	if buf.String() != expected {
 		t.Fatalf("Expected %q but got %q", expected, buf.String())
 	}
 }
+
+func TestCommand_Usage_typedDefaults(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Int("-p", "-port", 80, "Port")
+	rootCommand.Bool("-v", "-verbose", false, "Verbose output")
+	rootCommand.StringSlice("-t", "-tags", []string{"a", "b"}, "Tags")
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	rootCommand.Usage()
+
+	for _, expected := range []string{"Port (default 80)\n", "Verbose output\n", "Tags (default a,b)\n"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("Expected %q in %q", expected, buf.String())
+		}
+	}
+}
+
+func TestCommand_Usage_aliases(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	removeCommand := cli.NewCommand("remove", "remove Description")
+	removeCommand.Aliases = []string{"rm"}
+	rootCommand.AddCommand(removeCommand)
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-help"}, "Commands:\n  remove, rm  remove Description\n"},
+		{[]string{"rm", "-help"}, "usage: programName remove [-help] [args]\n\nAliases:\n  rm\n\nFlags:\n"},
+	}
+
+	for _, tc := range testCases {
+		buf.Reset()
+
+		err := cli.ExecuteArgs(rootCommand, tc.args)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		if !strings.Contains(buf.String(), tc.expected) {
+			t.Fatalf("Expected %q in %q", tc.expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FlagValidator validates the value of a flag.
+type FlagValidator func(value string) error
+
+// OneOf returns a FlagValidator that fails if the value is not one of the
+// given choices.
+func OneOf(choices ...string) FlagValidator {
+	return func(value string) error {
+		for _, choice := range choices {
+			if value == choice {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("must be one of %s", strings.Join(choices, ", "))
+	}
+}
+
+// MatchRegexp returns a FlagValidator that fails if the value does not match
+// the regular expression. It panics if the expression can not be parsed.
+func MatchRegexp(expr string) FlagValidator {
+	re := regexp.MustCompile(expr)
+
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match %s", expr)
+		}
+
+		return nil
+	}
+}
+
+// InRange returns a FlagValidator that fails if the value is not a number
+// between min and max, both included.
+func InRange(min float64, max float64) FlagValidator {
+	return func(value string) error {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+
+		if v < min || v > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+
+		return nil
+	}
+}
+
+// applyDefaults sets the flags of this command, including the ones of its
+// parent commands, that were not supplied from any source to their Default value.
+func (c *Command) applyDefaults() error {
+	for _, flag := range c.pathFlags() {
+		if flag.source != sourceDefault || flag.Default == "" {
+			continue
+		}
+
+		err := flag.Set(flag.Default)
+		if err != nil {
+			return &InvalidFlagValueError{
+				Command: flag.owner,
+				Flag:    flagDisplayName(flag),
+				Value:   flag.Default,
+				Err:     fmt.Errorf("default value: %w", err),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFlags checks the flags of this command, including the ones of its
+// parent commands. Required flags must have been supplied or have a default
+// value, supplied values must pass the flag validators and the flag groups
+// constraints must be met.
+//
+// Every violation found is returned in a single *FlagValidationError.
+func (c *Command) validateFlags() error {
+	errs := make([]error, 0)
+
+	for _, flag := range c.pathFlags() {
+		if flag.source == sourceDefault {
+			if flag.Required && flagDefault(flag) == "" {
+				errs = append(errs, &RequiredFlagError{Command: flag.owner, Flag: flagDisplayName(flag)})
+			}
+			continue
+		}
+
+		for _, validator := range flag.Validators {
+			err := validator(flag.Value)
+			if err != nil {
+				errs = append(errs, &InvalidFlagValueError{
+					Command: flag.owner,
+					Flag:    flagDisplayName(flag),
+					Value:   flag.Value,
+					Err:     err,
+				})
+			}
+		}
+	}
+
+	errs = append(errs, c.validateFlagGroups()...)
+
+	if len(errs) > 0 {
+		return &FlagValidationError{Command: c, Errors: errs}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/goombaio/cli"
+)
+
+func TestFlag_Default(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-m", LongName: "-mode", Default: "fast"})
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-o", LongName: "-output", Default: "json"})
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-output", "text"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if rootCommand.FlagName("-mode").Value != "fast" {
+		t.Fatalf("Expected %q but got %q", "fast", rootCommand.FlagName("-mode").Value)
+	}
+	if rootCommand.FlagName("-output").Value != "text" {
+		t.Fatalf("Expected %q but got %q", "text", rootCommand.FlagName("-output").Value)
+	}
+}
+
+func TestFlag_Required(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-u", LongName: "-user", Required: true})
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-p", LongName: "-password", Required: true})
+	rootCommand.Run = func(c *cli.Command) error {
+		t.Fatalf("Expected Run not to be called")
+
+		return nil
+	}
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-user", "admin"})
+	var validationErr *cli.FlagValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *cli.FlagValidationError but got %#v", err)
+	}
+	if len(validationErr.Errors) != 1 {
+		t.Fatalf("Expected 1 violation but got %d", len(validationErr.Errors))
+	}
+
+	var requiredErr *cli.RequiredFlagError
+	if !errors.As(validationErr.Errors[0], &requiredErr) || requiredErr.Flag != "-password" {
+		t.Fatalf("Expected required -password violation but got %s", validationErr.Errors[0])
+	}
+}
+
+func TestFlag_Required_default(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-m", LongName: "-mode", Description: "Mode", Default: "a", Required: true})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	if value := rootCommand.FlagName("-mode").Value; value != "a" {
+		t.Fatalf("Expected %q but got %q", "a", value)
+	}
+
+	rootCommand.Usage()
+	expected := "  -m, -mode  Mode (default a)\n"
+	if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+		t.Fatalf("Expected %q in %q", expected, buf.String())
+	}
+}
+
+func TestFlag_Required_help(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-u", LongName: "-user", Required: true})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-help"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+}
+
+func TestFlag_Validators(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{LongName: "-format", Validators: []cli.FlagValidator{cli.OneOf("json", "text")}})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-name", Validators: []cli.FlagValidator{cli.MatchRegexp("^[a-z]+$")}})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-ratio", Validators: []cli.FlagValidator{cli.InRange(0, 1)}})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-level", Validators: []cli.FlagValidator{cli.InRange(0, 9)}})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-unset", Validators: []cli.FlagValidator{cli.OneOf("a")}})
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-format=xml", "-name=Foo", "-ratio=2", "-level=x"})
+	var validationErr *cli.FlagValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *cli.FlagValidationError but got %#v", err)
+	}
+	if len(validationErr.Errors) != 4 {
+		t.Fatalf("Expected 4 violations but got %d: %s", len(validationErr.Errors), err)
+	}
+
+	err = cli.ExecuteArgs(rootCommand, []string{"-format=json", "-name=foo", "-ratio=0.5", "-level=9"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+}
+
+func TestFlagValidationError_Error(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{LongName: "-user", Required: true})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-format", Validators: []cli.FlagValidator{cli.OneOf("json", "text")}})
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-format=xml"})
+
+	expected := "invalid flags for \"programName\":\n"
+	expected += "\trequired flag -user of \"programName\" not set\n"
+	expected += "\tinvalid value \"xml\" for flag -format of \"programName\": must be one of json, text"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %q but got %v", expected, err)
+	}
+}
+
+func TestCommand_Usage_defaultAndRequired(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-m", LongName: "-mode", Description: "Mode", Default: "fast"})
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-u", LongName: "-user", Description: "User", Required: true})
+
+	buf := new(bytes.Buffer)
+	rootCommand.SetOutput(buf)
+	rootCommand.Usage()
+
+	for _, expected := range []string{
+		"  -m, -mode  Mode (default fast)\n",
+		"  -u, -user  User (required)\n",
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+			t.Fatalf("Expected %q in %q", expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,499 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value is the interface to the dynamic value stored in a flag.
+//
+// Set is called once for every occurrence of the flag on the command line,
+// with the string representation of the value. String returns the current
+// value, and it is what gets stored in Flag.Value.
+type Value interface {
+	String() string
+	Set(string) error
+}
+
+// boolFlag is implemented by the values of boolean flags, those that do not
+// need a value on the command line.
+type boolFlag interface {
+	Value
+	IsBoolFlag() bool
+}
+
+// resetter is implemented by the values that can not be reset to their
+// initial value by setting it, as the ones that accumulate the occurrences of
+// the flag.
+type resetter interface {
+	reset()
+}
+
+// -- bool Value
+type boolValue bool
+
+func newBoolValue(val bool, p *bool) *boolValue {
+	*p = val
+	return (*boolValue)(p)
+}
+
+func (b *boolValue) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*b = boolValue(v)
+	return nil
+}
+
+func (b *boolValue) String() string { return strconv.FormatBool(bool(*b)) }
+
+func (b *boolValue) IsBoolFlag() bool { return true }
+
+// -- int Value
+type intValue int
+
+func newIntValue(val int, p *int) *intValue {
+	*p = val
+	return (*intValue)(p)
+}
+
+func (i *intValue) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return err
+	}
+	*i = intValue(v)
+	return nil
+}
+
+func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+
+// -- int64 Value
+type int64Value int64
+
+func newInt64Value(val int64, p *int64) *int64Value {
+	*p = val
+	return (*int64Value)(p)
+}
+
+func (i *int64Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return err
+	}
+	*i = int64Value(v)
+	return nil
+}
+
+func (i *int64Value) String() string { return strconv.FormatInt(int64(*i), 10) }
+
+// -- uint Value
+type uintValue uint
+
+func newUintValue(val uint, p *uint) *uintValue {
+	*p = val
+	return (*uintValue)(p)
+}
+
+func (i *uintValue) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
+	if err != nil {
+		return err
+	}
+	*i = uintValue(v)
+	return nil
+}
+
+func (i *uintValue) String() string { return strconv.FormatUint(uint64(*i), 10) }
+
+// -- float64 Value
+type float64Value float64
+
+func newFloat64Value(val float64, p *float64) *float64Value {
+	*p = val
+	return (*float64Value)(p)
+}
+
+func (f *float64Value) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f = float64Value(v)
+	return nil
+}
+
+func (f *float64Value) String() string { return strconv.FormatFloat(float64(*f), 'g', -1, 64) }
+
+// -- string Value
+type stringValue string
+
+func newStringValue(val string, p *string) *stringValue {
+	*p = val
+	return (*stringValue)(p)
+}
+
+func (s *stringValue) Set(val string) error {
+	*s = stringValue(val)
+	return nil
+}
+
+func (s *stringValue) String() string { return string(*s) }
+
+// -- time.Duration Value
+type durationValue time.Duration
+
+func newDurationValue(val time.Duration, p *time.Duration) *durationValue {
+	*p = val
+	return (*durationValue)(p)
+}
+
+func (d *durationValue) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = durationValue(v)
+	return nil
+}
+
+func (d *durationValue) String() string { return time.Duration(*d).String() }
+
+// -- []string Value
+//
+// Values are comma separated, and every occurrence of the flag appends to the
+// list. The first occurrence replaces the default value.
+type stringSliceValue struct {
+	value   *[]string
+	initial []string
+	changed bool
+}
+
+func newStringSliceValue(val []string, p *[]string) *stringSliceValue {
+	*p = val
+	return &stringSliceValue{value: p, initial: val}
+}
+
+func (s *stringSliceValue) Set(val string) error {
+	if !s.changed {
+		*s.value = []string{}
+		s.changed = true
+	}
+	*s.value = append(*s.value, splitList(val)...)
+	return nil
+}
+
+func (s *stringSliceValue) String() string { return strings.Join(*s.value, ",") }
+
+func (s *stringSliceValue) reset() {
+	*s.value = s.initial
+	s.changed = false
+}
+
+// -- map[string]string Value
+//
+// Values are comma separated key=value pairs, and every occurrence of the
+// flag adds to the map. The first occurrence replaces the default value.
+type stringMapValue struct {
+	value   *map[string]string
+	initial map[string]string
+	changed bool
+}
+
+func newStringMapValue(val map[string]string, p *map[string]string) *stringMapValue {
+	*p = val
+	return &stringMapValue{value: p, initial: val}
+}
+
+func (m *stringMapValue) Set(val string) error {
+	pairs := make(map[string]string)
+	for _, pair := range splitList(val) {
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			return fmt.Errorf("%q is not a key=value pair", pair)
+		}
+		pairs[pair[:i]] = pair[i+1:]
+	}
+
+	if !m.changed || *m.value == nil {
+		*m.value = make(map[string]string)
+		m.changed = true
+	}
+	for k, v := range pairs {
+		(*m.value)[k] = v
+	}
+	return nil
+}
+
+func (m *stringMapValue) String() string {
+	keys := make([]string, 0, len(*m.value))
+	for k := range *m.value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+(*m.value)[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m *stringMapValue) reset() {
+	*m.value = m.initial
+	m.changed = false
+}
+
+// splitList splits a comma separated list of values.
+func splitList(val string) []string {
+	if val == "" {
+		return []string{}
+	}
+
+	return strings.Split(val, ",")
+}
+
+// Var defines a flag with the specified names and description backed by a
+// Value, and adds it to this command.
+//
+// The current value of the Value is used as the flag default value.
+func (c *Command) Var(value Value, shortName string, longName string, description string) *Flag {
+	flag := &Flag{
+		ShortName:   shortName,
+		LongName:    longName,
+		Description: description,
+		Value:       value.String(),
+		Var:         value,
+	}
+	c.AddFlag(flag)
+
+	return flag
+}
+
+// BoolVar defines a bool flag. The argument p points to a bool variable in
+// which to store the value of the flag.
+func (c *Command) BoolVar(p *bool, shortName string, longName string, value bool, description string) *Flag {
+	return c.Var(newBoolValue(value, p), shortName, longName, description)
+}
+
+// Bool defines a bool flag. The return value is the address of a bool
+// variable that stores the value of the flag.
+func (c *Command) Bool(shortName string, longName string, value bool, description string) *bool {
+	p := new(bool)
+	c.BoolVar(p, shortName, longName, value, description)
+	return p
+}
+
+// IntVar defines an int flag. The argument p points to an int variable in
+// which to store the value of the flag.
+func (c *Command) IntVar(p *int, shortName string, longName string, value int, description string) *Flag {
+	return c.Var(newIntValue(value, p), shortName, longName, description)
+}
+
+// Int defines an int flag. The return value is the address of an int
+// variable that stores the value of the flag.
+func (c *Command) Int(shortName string, longName string, value int, description string) *int {
+	p := new(int)
+	c.IntVar(p, shortName, longName, value, description)
+	return p
+}
+
+// Int64Var defines an int64 flag. The argument p points to an int64 variable
+// in which to store the value of the flag.
+func (c *Command) Int64Var(p *int64, shortName string, longName string, value int64, description string) *Flag {
+	return c.Var(newInt64Value(value, p), shortName, longName, description)
+}
+
+// Int64 defines an int64 flag. The return value is the address of an int64
+// variable that stores the value of the flag.
+func (c *Command) Int64(shortName string, longName string, value int64, description string) *int64 {
+	p := new(int64)
+	c.Int64Var(p, shortName, longName, value, description)
+	return p
+}
+
+// UintVar defines an uint flag. The argument p points to an uint variable in
+// which to store the value of the flag.
+func (c *Command) UintVar(p *uint, shortName string, longName string, value uint, description string) *Flag {
+	return c.Var(newUintValue(value, p), shortName, longName, description)
+}
+
+// Uint defines an uint flag. The return value is the address of an uint
+// variable that stores the value of the flag.
+func (c *Command) Uint(shortName string, longName string, value uint, description string) *uint {
+	p := new(uint)
+	c.UintVar(p, shortName, longName, value, description)
+	return p
+}
+
+// Float64Var defines a float64 flag. The argument p points to a float64
+// variable in which to store the value of the flag.
+func (c *Command) Float64Var(p *float64, shortName string, longName string, value float64, description string) *Flag {
+	return c.Var(newFloat64Value(value, p), shortName, longName, description)
+}
+
+// Float64 defines a float64 flag. The return value is the address of a
+// float64 variable that stores the value of the flag.
+func (c *Command) Float64(shortName string, longName string, value float64, description string) *float64 {
+	p := new(float64)
+	c.Float64Var(p, shortName, longName, value, description)
+	return p
+}
+
+// StringVar defines a string flag. The argument p points to a string variable
+// in which to store the value of the flag.
+func (c *Command) StringVar(p *string, shortName string, longName string, value string, description string) *Flag {
+	return c.Var(newStringValue(value, p), shortName, longName, description)
+}
+
+// String defines a string flag. The return value is the address of a string
+// variable that stores the value of the flag.
+func (c *Command) String(shortName string, longName string, value string, description string) *string {
+	p := new(string)
+	c.StringVar(p, shortName, longName, value, description)
+	return p
+}
+
+// DurationVar defines a time.Duration flag. The argument p points to a
+// time.Duration variable in which to store the value of the flag.
+func (c *Command) DurationVar(p *time.Duration, shortName string, longName string, value time.Duration, description string) *Flag {
+	return c.Var(newDurationValue(value, p), shortName, longName, description)
+}
+
+// Duration defines a time.Duration flag. The return value is the address of a
+// time.Duration variable that stores the value of the flag.
+func (c *Command) Duration(shortName string, longName string, value time.Duration, description string) *time.Duration {
+	p := new(time.Duration)
+	c.DurationVar(p, shortName, longName, value, description)
+	return p
+}
+
+// StringSliceVar defines a []string flag. The argument p points to a []string
+// variable in which to store the value of the flag.
+func (c *Command) StringSliceVar(p *[]string, shortName string, longName string, value []string, description string) *Flag {
+	return c.Var(newStringSliceValue(value, p), shortName, longName, description)
+}
+
+// StringSlice defines a []string flag. The return value is the address of a
+// []string variable that stores the value of the flag.
+func (c *Command) StringSlice(shortName string, longName string, value []string, description string) *[]string {
+	p := new([]string)
+	c.StringSliceVar(p, shortName, longName, value, description)
+	return p
+}
+
+// StringMapVar defines a map[string]string flag. The argument p points to a
+// map[string]string variable in which to store the value of the flag.
+func (c *Command) StringMapVar(p *map[string]string, shortName string, longName string, value map[string]string, description string) *Flag {
+	return c.Var(newStringMapValue(value, p), shortName, longName, description)
+}
+
+// StringMap defines a map[string]string flag. The return value is the address
+// of a map[string]string variable that stores the value of the flag.
+func (c *Command) StringMap(shortName string, longName string, value map[string]string, description string) *map[string]string {
+	p := new(map[string]string)
+	c.StringMapVar(p, shortName, longName, value, description)
+	return p
+}
+
+// GetBool returns the value of the flag with the given name as a bool.
+func (c *Command) GetBool(name string) (bool, error) {
+	var v bool
+	err := c.getFlagValue(name, newBoolValue(false, &v))
+	return v, err
+}
+
+// GetInt returns the value of the flag with the given name as an int.
+func (c *Command) GetInt(name string) (int, error) {
+	var v int
+	err := c.getFlagValue(name, newIntValue(0, &v))
+	return v, err
+}
+
+// GetInt64 returns the value of the flag with the given name as an int64.
+func (c *Command) GetInt64(name string) (int64, error) {
+	var v int64
+	err := c.getFlagValue(name, newInt64Value(0, &v))
+	return v, err
+}
+
+// GetUint returns the value of the flag with the given name as an uint.
+func (c *Command) GetUint(name string) (uint, error) {
+	var v uint
+	err := c.getFlagValue(name, newUintValue(0, &v))
+	return v, err
+}
+
+// GetFloat64 returns the value of the flag with the given name as a float64.
+func (c *Command) GetFloat64(name string) (float64, error) {
+	var v float64
+	err := c.getFlagValue(name, newFloat64Value(0, &v))
+	return v, err
+}
+
+// GetString returns the value of the flag with the given name as a string.
+func (c *Command) GetString(name string) (string, error) {
+	var v string
+	err := c.getFlagValue(name, newStringValue("", &v))
+	return v, err
+}
+
+// GetDuration returns the value of the flag with the given name as a
+// time.Duration.
+func (c *Command) GetDuration(name string) (time.Duration, error) {
+	var v time.Duration
+	err := c.getFlagValue(name, newDurationValue(0, &v))
+	return v, err
+}
+
+// GetStringSlice returns the value of the flag with the given name as a
+// []string.
+func (c *Command) GetStringSlice(name string) ([]string, error) {
+	var v []string
+	err := c.getFlagValue(name, newStringSliceValue(nil, &v))
+	return v, err
+}
+
+// GetStringMap returns the value of the flag with the given name as a
+// map[string]string.
+func (c *Command) GetStringMap(name string) (map[string]string, error) {
+	var v map[string]string
+	err := c.getFlagValue(name, newStringMapValue(nil, &v))
+	return v, err
+}
+
+// getFlagValue converts the value of the flag with the given name into value.
+func (c *Command) getFlagValue(name string, value Value) error {
+	flag := c.FlagName(name)
+	if flag == nil {
+		return &UnknownFlagError{Command: c, Flag: name}
+	}
+
+	err := value.Set(flag.Value)
+	if err != nil {
+		return &InvalidFlagValueError{Command: c, Flag: name, Value: flag.Value, Err: err}
+	}
+
+	return nil
+}
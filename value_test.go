@@ -0,0 +1,185 @@
+// Copyright © 2018, Goomba project Authors. All rights reserved.
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with this
+// work for additional information regarding copyright ownership.  The ASF
+// licenses this file to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package cli_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goombaio/cli"
+)
+
+func TestCommand_typedFlags(t *testing.T) {
+	args := []string{
+		"-b",
+		"-int=-42",
+		"-int64", "64",
+		"-uint=7",
+		"-float64", "1.5",
+		"-string", "foo",
+		"-duration=1m30s",
+		"-slice=a,b", "-slice", "c",
+		"-map", "k1=v1,k2=v2",
+	}
+
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	b := rootCommand.Bool("-b", "-bool", false, "Bool flag")
+	i := rootCommand.Int("-i", "-int", 0, "Int flag")
+	i64 := rootCommand.Int64("-i64", "-int64", 0, "Int64 flag")
+	u := rootCommand.Uint("-u", "-uint", 0, "Uint flag")
+	f := rootCommand.Float64("-f", "-float64", 0, "Float64 flag")
+	s := rootCommand.String("-s", "-string", "", "String flag")
+	d := rootCommand.Duration("-d", "-duration", time.Second, "Duration flag")
+	sl := rootCommand.StringSlice("-sl", "-slice", []string{"default"}, "String slice flag")
+	m := rootCommand.StringMap("-m", "-map", nil, "String map flag")
+
+	err := cli.ExecuteArgs(rootCommand, args)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if !*b {
+		t.Fatalf("Expected %v but got %v", true, *b)
+	}
+	if *i != -42 {
+		t.Fatalf("Expected %d but got %d", -42, *i)
+	}
+	if *i64 != 64 {
+		t.Fatalf("Expected %d but got %d", 64, *i64)
+	}
+	if *u != 7 {
+		t.Fatalf("Expected %d but got %d", 7, *u)
+	}
+	if *f != 1.5 {
+		t.Fatalf("Expected %v but got %v", 1.5, *f)
+	}
+	if *s != "foo" {
+		t.Fatalf("Expected %q but got %q", "foo", *s)
+	}
+	if *d != 90*time.Second {
+		t.Fatalf("Expected %s but got %s", 90*time.Second, *d)
+	}
+	if !reflect.DeepEqual(*sl, []string{"a", "b", "c"}) {
+		t.Fatalf("Expected %q but got %q", []string{"a", "b", "c"}, *sl)
+	}
+	if !reflect.DeepEqual(*m, map[string]string{"k1": "v1", "k2": "v2"}) {
+		t.Fatalf("Expected %v but got %v", map[string]string{"k1": "v1", "k2": "v2"}, *m)
+	}
+}
+
+func TestCommand_typedFlags_defaults(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	i := rootCommand.Int("-i", "-int", 3, "Int flag")
+	sl := rootCommand.StringSlice("-sl", "-slice", []string{"a", "b"}, "String slice flag")
+
+	err := cli.ExecuteArgs(rootCommand, []string{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if *i != 3 {
+		t.Fatalf("Expected %d but got %d", 3, *i)
+	}
+	if rootCommand.FlagName("-int").Value != "3" {
+		t.Fatalf("Expected %q but got %q", "3", rootCommand.FlagName("-int").Value)
+	}
+	if rootCommand.FlagName("-slice").Value != "a,b" {
+		t.Fatalf("Expected %q but got %q", "a,b", rootCommand.FlagName("-slice").Value)
+	}
+	if !reflect.DeepEqual(*sl, []string{"a", "b"}) {
+		t.Fatalf("Expected %q but got %q", []string{"a", "b"}, *sl)
+	}
+}
+
+func TestCommand_typedFlags_invalidValue(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.Int("-i", "-int", 0, "Int flag")
+	rootCommand.Run = func(c *cli.Command) error {
+		t.Fatalf("Expected Run not to be called")
+
+		return nil
+	}
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-int", "foo"})
+	if err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+}
+
+func TestCommand_getters(t *testing.T) {
+	rootCommand := cli.NewCommand("programName", "rootCommand Description")
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-p", LongName: "-port", Value: "80"})
+	rootCommand.AddFlag(&cli.Flag{ShortName: "-t", LongName: "-timeout", Value: "1s"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-tags"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-labels"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-ratio"})
+	rootCommand.AddFlag(&cli.Flag{LongName: "-debug", Value: "false"})
+
+	err := cli.ExecuteArgs(rootCommand, []string{"-port=8080", "-timeout", "5s", "-tags", "a,b", "-labels=k=v", "-ratio=0.5", "-debug"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	port, err := rootCommand.GetInt("-port")
+	if err != nil || port != 8080 {
+		t.Fatalf("Expected %d but got %d (%v)", 8080, port, err)
+	}
+	port64, err := rootCommand.GetInt64("port")
+	if err != nil || port64 != 8080 {
+		t.Fatalf("Expected %d but got %d (%v)", 8080, port64, err)
+	}
+	uport, err := rootCommand.GetUint("-p")
+	if err != nil || uport != 8080 {
+		t.Fatalf("Expected %d but got %d (%v)", 8080, uport, err)
+	}
+	timeout, err := rootCommand.GetDuration("-timeout")
+	if err != nil || timeout != 5*time.Second {
+		t.Fatalf("Expected %s but got %s (%v)", 5*time.Second, timeout, err)
+	}
+	tags, err := rootCommand.GetStringSlice("-tags")
+	if err != nil || !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Fatalf("Expected %q but got %q (%v)", []string{"a", "b"}, tags, err)
+	}
+	labels, err := rootCommand.GetStringMap("-labels")
+	if err != nil || !reflect.DeepEqual(labels, map[string]string{"k": "v"}) {
+		t.Fatalf("Expected %v but got %v (%v)", map[string]string{"k": "v"}, labels, err)
+	}
+	ratio, err := rootCommand.GetFloat64("-ratio")
+	if err != nil || ratio != 0.5 {
+		t.Fatalf("Expected %v but got %v (%v)", 0.5, ratio, err)
+	}
+	debug, err := rootCommand.GetBool("-debug")
+	if err != nil || !debug {
+		t.Fatalf("Expected %v but got %v (%v)", true, debug, err)
+	}
+	name, err := rootCommand.GetString("-timeout")
+	if err != nil || name != "5s" {
+		t.Fatalf("Expected %q but got %q (%v)", "5s", name, err)
+	}
+
+	_, err = rootCommand.GetInt("-tags")
+	if err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+
+	_, err = rootCommand.GetInt("-undefined")
+	if err == nil {
+		t.Fatalf("Expected error but got nil")
+	}
+}